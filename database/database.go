@@ -2,8 +2,10 @@ package database
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
-	_ "github.com/go-sql-driver/mysql"
+
+	"github.com/go-sql-driver/mysql"
 )
 
 var DB *sql.DB
@@ -24,3 +26,9 @@ func InitDB() error {
 	fmt.Println("Conexão com o banco de dados estabelecida!")
 	return nil
 }
+
+// IsDuplicateKey indica se o erro é uma violação de chave única do MySQL
+func IsDuplicateKey(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062
+}
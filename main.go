@@ -1,13 +1,17 @@
 package main
 
 import (
+	"database/sql"
 	"dynastyTracker/database"
 	"dynastyTracker/models"
 	"dynastyTracker/services"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -26,7 +30,7 @@ func main() {
 		enableCors(w, r) // Sem o ponteiro, passando diretamente o http.ResponseWriter
 		playersHandler(w, r)
 	})
-	http.HandleFunc("/api/players/", playerHandler) // Busca jogador por ID
+	http.HandleFunc("/api/players/", playerHandler) // Busca jogador por ID, perfil (/{id}/profile) e jogos (/{id}/game-log)
 
 	// Calendário
 	http.HandleFunc("/api/schedule", scheduleHandler)      // Lista e adiciona jogos no calendário
@@ -51,9 +55,97 @@ func main() {
 	http.HandleFunc("/api/reports/top-players", topPlayersBySeasonHandler)
 	http.HandleFunc("/api/reports/team-season-comparison", teamSeasonComparisonHandler)
 	http.HandleFunc("/api/reports/record-break-prediction", recordBreakPredictionHandler)
+	http.HandleFunc("/api/reports/player-comparison", playerComparisonHandler) // ?player_ids=1,2&record_ids=3&align=season|year
+	http.HandleFunc("/api/reports/redshirt-eligibility", redshirtEligibilityHandler)
+
+	// Elegibilidade e virada de temporada
+	http.HandleFunc("/api/players/redshirt", redshirtHandler)
+	http.HandleFunc("/api/season/rollover", seasonRolloverHandler)
+
+	// Escalação (depth chart)
+	http.HandleFunc("/api/depth-chart", depthChartHandler)
+	http.HandleFunc("/api/depth-chart/history", depthChartHistoryHandler)
+	http.HandleFunc("/api/depth-chart/slots", depthChartSlotsHandler)
+
+	// Lesões
+	http.HandleFunc("/api/injuries", injuriesHandler) // Lista e adiciona lesões
+	http.HandleFunc("/api/injuries/", injuryHandler)  // Busca e atualiza lesão por ID
+	http.HandleFunc("/api/reports/unavailable", unavailablePlayersHandler)
+	http.HandleFunc("/api/reports/games-missed", gamesMissedHandler)
+
+	// Estatísticas por jogo
+	http.HandleFunc("/api/player-game-stats", addPlayerGameStatsHandler)
+
+	// Atributos e arquétipos por posição
+	http.HandleFunc("/api/attributes/schema", attributeSchemaHandler)
+
+	// Dev trait e histórico de ratings
+	http.HandleFunc("/api/players/dev-trait/reveal", devTraitRevealHandler)
+	http.HandleFunc("/api/players/dev-trait/upgrade", devTraitUpgradeHandler)
+	http.HandleFunc("/api/players/dev-trait/history", devTraitHistoryHandler)
+	http.HandleFunc("/api/player-ratings", playerRatingsHandler)
+	http.HandleFunc("/api/reports/dev-trait-impact", devTraitImpactHandler)
+
+	// Draft da NFL
+	http.HandleFunc("/api/draft", draftHandler)       // Lista e adiciona declarações para o draft
+	http.HandleFunc("/api/draft/", draftEntryHandler) // Busca e registra resultado por ID
+	http.HandleFunc("/api/reports/draft", draftReportHandler)
+
+	// Saídas do elenco (graduação, draft, transferência, desligamento, questões médicas)
+	http.HandleFunc("/api/departures", departuresHandler) // Lista e registra saídas
+	http.HandleFunc("/api/departures/", departureHandler) // Busca, corrige e desfaz saída por ID
+	http.HandleFunc("/api/reports/attrition", attritionReportHandler)
+
+	// Regras de elenco (bolsas, elenco total, posições e anos acadêmicos)
+	http.HandleFunc("/api/roster-rules", rosterRulesHandler) // Consulta e substitui as regras do time
+	http.HandleFunc("/api/reports/roster-compliance", rosterComplianceHandler)
+
+	// Identidade dos atletas (recruta → jogador → saída → recorde histórico)
+	http.HandleFunc("/api/people/", personHandler)                 // Busca por ID e separa registros (POST /api/people/{id}/split)
+	http.HandleFunc("/api/people/merge", mergePeopleHandler)       // Une duas pessoas
+	http.HandleFunc("/api/people/backfill", backfillPeopleHandler) // Cria pessoas para registros antigos
+
+	// Busca aproximada em jogadores, recrutas, recordes, times e adversários
+	http.HandleFunc("/api/search", searchHandler) // ?q=jon smith&types=player,recruit&limit=25
+
+	// Números de camisa
+	http.HandleFunc("/api/jerseys", jerseysHandler)                       // Lista (?team_id=&year=), define (POST) e remove (DELETE ?player_id=&year=)
+	http.HandleFunc("/api/jerseys/suggestions", jerseySuggestionsHandler) // Sugestões para jogadores sem número, como recrutas promovidos
+	http.HandleFunc("/api/jerseys/retired", retiredNumbersHandler)        // Números aposentados do time
+	http.HandleFunc("/api/reports/jersey-conflicts", jerseyConflictsHandler)
+
+	// Quadro de recrutamento (alvos antes de assinar)
+	http.HandleFunc("/api/recruiting-board", recruitingBoardHandler)             // Lista (?team_id=&year=&stage=) e adiciona alvos
+	http.HandleFunc("/api/recruiting-board/", prospectHandler)                   // Por ID; /weeks (esforço semanal), /visit, /sign e /competitors
+	http.HandleFunc("/api/reports/recruiting-battles", recruitingBattlesHandler) // Retrospecto contra escolas rivais (?team_id=&from_year=&to_year=)
+
+	// Rankings de classes de recrutamento
+	http.HandleFunc("/api/teams/conferences", teamConferencesHandler) // Conferência por temporada (?year=) e realinhamentos (POST)
+	http.HandleFunc("/api/reports/recruiting-class-rankings", recruitingClassRankingsHandler)
+	http.HandleFunc("/api/reports/recruiting-retrospective", recruitingRetrospectiveHandler) // Acertos e erros por recruta e classe (?team_id=&year=)
+	http.HandleFunc("/api/reports/recruiting-geography", recruitingGeographyHandler)         // Por estado e cidade (?team_id=&from_year=&to_year=&source=)
+	http.HandleFunc("/api/reports/recruiting-geography/geojson", recruitingGeoJSONHandler)   // Mapa offline (?level=city|state)
+	http.HandleFunc("/api/roster-composition", rosterCompositionHandler)                     // Elenco ideal por posição (GET ?team_id=, PUT)
+	http.HandleFunc("/api/reports/positional-needs", positionalNeedsHandler)                 // O que recrutar (?team_id=&year=)
+	http.HandleFunc("/api/reports/recruiting-roi", recruitingROIHandler)                     // Estrelas x produção (?team_id=&from_year=&to_year=&position=)
+
+	// Portal de transferências (chegadas e saídas)
+	http.HandleFunc("/api/portal", portalHandler)          // Lista (?team_id=&year=&direction=&status=) e registra movimentações
+	http.HandleFunc("/api/portal/", portalTransferHandler) // Por ID; /commit, /withdraw e /prior-stats
+	http.HandleFunc("/api/reports/portal", portalReportHandler)
+
+	// Prêmios e honrarias (Heisman, All-American, All-Conference, semanais)
+	http.HandleFunc("/api/awards", awardsHandler)             // Lista e cadastra prêmios
+	http.HandleFunc("/api/awards/honors", awardHonorsHandler) // Lista e registra vencedores/finalistas
+	http.HandleFunc("/api/awards/honors/", awardHonorHandler) // Exclui por ID
+	http.HandleFunc("/api/players/trophy-case", playerTrophyCaseHandler)
+	http.HandleFunc("/api/teams/trophy-case", teamTrophyCaseHandler)
 
 	// Recrutas
+	http.HandleFunc("/api/recruits", recruitsHandler) // Lista (filtros, ?page=&page_size=&sort=&order=) e adiciona
+	http.HandleFunc("/api/recruits/", recruitHandler) // Busca, corrige e exclui recruta por ID
 	http.HandleFunc("/api/recruits/add", addRecruitHandler)
+	http.HandleFunc("/api/recruits/promote", promoteRecruitsHandler) // Promove a classe assinada no ano anterior (POST {year}); refaz os que ficaram de fora
 	http.HandleFunc("/api/players/add", func(w http.ResponseWriter, r *http.Request) {
 		enableCors(w, r) // Sem o ponteiro, passando diretamente o http.ResponseWriter
 		addPlayerHandler(w, r)
@@ -111,6 +203,15 @@ func playersHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func playerHandler(w http.ResponseWriter, r *http.Request) {
+	if strings.HasSuffix(r.URL.Path, "/profile") {
+		playerProfileHandler(w, r)
+		return
+	}
+	if strings.HasSuffix(r.URL.Path, "/game-log") {
+		playerGameLogHandler(w, r)
+		return
+	}
+
 	id := extractID(r.URL.Path)
 	switch r.Method {
 	case http.MethodGet:
@@ -123,14 +224,30 @@ func playerHandler(w http.ResponseWriter, r *http.Request) {
 		json.NewEncoder(w).Encode(player)
 
 	case http.MethodPut: // Adicionando a funcionalidade PUT
-		var player models.Player
-		err := json.NewDecoder(r.Body).Decode(&player)
+		// A requisição é aplicada sobre o cadastro atual: campos omitidos mantêm o valor gravado
+		player, err := services.GetPlayer(id)
+		if err == sql.ErrNoRows {
+			http.Error(w, "Jogador não encontrado", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			fmt.Printf("Erro ao buscar jogador: %v\n", err)
+			http.Error(w, "Erro ao atualizar jogador", http.StatusInternalServerError)
+			return
+		}
+		player.Attributes = nil // Atributos só são substituídos quando enviados
+		err = json.NewDecoder(r.Body).Decode(&player)
 		if err != nil {
 			http.Error(w, "Erro ao decodificar jogador", http.StatusBadRequest)
 			return
 		}
 		player.PlayerID = id // Certifique-se de usar o ID correto
 		err = services.UpdatePlayer(player)
+		var ruleErr *services.RosterRuleError
+		if errors.As(err, &ruleErr) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
 		if err != nil {
 			http.Error(w, "Erro ao atualizar jogador", http.StatusInternalServerError)
 			return
@@ -140,6 +257,38 @@ func playerHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func playerProfileHandler(w http.ResponseWriter, r *http.Request) {
+	id := extractID(strings.TrimSuffix(r.URL.Path, "/profile"))
+	profile, err := services.GetPlayerProfile(id)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Jogador não encontrado", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Erro ao montar perfil do jogador", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(profile)
+}
+
+func playerGameLogHandler(w http.ResponseWriter, r *http.Request) {
+	id := extractID(strings.TrimSuffix(r.URL.Path, "/game-log"))
+	var year int
+	if param := r.URL.Query().Get("year"); param != "" {
+		year, _ = strconv.Atoi(param)
+	}
+	opponent := r.URL.Query().Get("opponent")
+
+	gameLog, err := services.GetPlayerGameLog(id, year, opponent)
+	if err != nil {
+		http.Error(w, "Erro ao obter jogos do jogador", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(gameLog)
+}
+
 func scheduleHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodGet:
@@ -292,19 +441,93 @@ func playerSearchHandler(w http.ResponseWriter, r *http.Request) {
 	position := r.URL.Query().Get("position")
 	teamIDParam := r.URL.Query().Get("team_id")
 
+	archetype := r.URL.Query().Get("archetype")
+
 	var teamID int
 	if teamIDParam != "" {
 		teamID, _ = strconv.Atoi(teamIDParam)
 	}
 
-	players, err := services.GetPlayersWithFilters(position, teamID)
+	// Filtros de atributos no formato min_<atributo>=N e max_<atributo>=N (ex: min_speed=92)
+	attributeFilters, err := parseAttributeFilters(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	page, err := services.GetPlayersWithFilters(position, teamID, archetype, attributeFilters, parseListOptions(r))
+	if errors.Is(err, services.ErrInvalidSort) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		http.Error(w, "Erro ao buscar jogadores", http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(players)
+	json.NewEncoder(w).Encode(page)
+}
+
+// parseAttributeFilters lê os parâmetros min_<atributo> e max_<atributo> da busca
+func parseAttributeFilters(query url.Values) ([]services.AttributeFilter, error) {
+	filtersByAttribute := make(map[string]*services.AttributeFilter)
+	var order []string
+	for key, values := range query {
+		var attribute string
+		var isMin bool
+		switch {
+		case strings.HasPrefix(key, "min_"):
+			attribute, isMin = strings.TrimPrefix(key, "min_"), true
+		case strings.HasPrefix(key, "max_"):
+			attribute = strings.TrimPrefix(key, "max_")
+		default:
+			continue
+		}
+
+		if !services.IsKnownAttribute(attribute) {
+			return nil, fmt.Errorf("Atributo desconhecido: '%s'", attribute)
+		}
+		value, err := strconv.Atoi(values[0])
+		if err != nil {
+			return nil, fmt.Errorf("Valor inválido para o parâmetro '%s'", key)
+		}
+
+		filter, ok := filtersByAttribute[attribute]
+		if !ok {
+			filter = &services.AttributeFilter{Attribute: attribute}
+			filtersByAttribute[attribute] = filter
+			order = append(order, attribute)
+		}
+		if isMin {
+			filter.Min = value
+		} else {
+			filter.Max = value
+		}
+	}
+
+	sort.Strings(order)
+	var filters []services.AttributeFilter
+	for _, attribute := range order {
+		filters = append(filters, *filtersByAttribute[attribute])
+	}
+	return filters, nil
+}
+
+func attributeSchemaHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	position := r.URL.Query().Get("position")
+	if position == "" {
+		json.NewEncoder(w).Encode(services.GetAttributeSchemas())
+		return
+	}
+
+	schema, ok := services.GetAttributeSchema(position)
+	if !ok {
+		http.Error(w, "Posição sem esquema de atributos", http.StatusNotFound)
+		return
+	}
+	json.NewEncoder(w).Encode(schema)
 }
 
 func recordSearchHandler(w http.ResponseWriter, r *http.Request) {
@@ -461,87 +684,1835 @@ func recordBreakPredictionHandler(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(prediction)
 }
 
-func addPlayerHandler(w http.ResponseWriter, r *http.Request) {
-	var player models.Player
-	// Decodificar o corpo da requisição JSON para a estrutura Player
-	err := json.NewDecoder(r.Body).Decode(&player)
+func redshirtEligibilityHandler(w http.ResponseWriter, r *http.Request) {
+	year, err := strconv.Atoi(r.URL.Query().Get("year"))
 	if err != nil {
-		http.Error(w, "Erro ao decodificar dados do jogador", http.StatusBadRequest)
+		http.Error(w, "Ano inválido", http.StatusBadRequest)
 		return
 	}
 
-	// Buscar o team_id com base no nome do time
-	teamID, err := getTeamIDByName(player.TeamName)
+	var teamID int
+	if teamIDParam := r.URL.Query().Get("team_id"); teamIDParam != "" {
+		teamID, _ = strconv.Atoi(teamIDParam)
+	}
+
+	report, err := services.GetRedshirtEligibility(year, teamID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Erro ao obter elegibilidade de redshirt", http.StatusInternalServerError)
 		return
 	}
 
-	// Atualizar o player com o team_id
-	player.TeamID = teamID
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
 
-	// Inserir o jogador no banco de dados
-	err = services.AddPlayer(player)
-	if err != nil {
-		http.Error(w, "Erro ao adicionar jogador", http.StatusInternalServerError)
-		return
+func redshirtHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		var request struct {
+			PlayerID int `json:"player_id"`
+			Year     int `json:"year"`
+		}
+		err := json.NewDecoder(r.Body).Decode(&request)
+		if err != nil {
+			http.Error(w, "Erro ao decodificar dados do redshirt", http.StatusBadRequest)
+			return
+		}
+
+		err = services.SetRedshirt(request.PlayerID, request.Year)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]string{"message": "Redshirt registrado com sucesso"})
 	}
+}
 
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(map[string]string{"message": "Jogador adicionado com sucesso"})
+func seasonRolloverHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		var request struct {
+			Year int `json:"year"`
+		}
+		err := json.NewDecoder(r.Body).Decode(&request)
+		if err != nil || request.Year == 0 {
+			http.Error(w, "Ano inválido", http.StatusBadRequest)
+			return
+		}
+
+		report, err := services.RolloverSeason(request.Year)
+		if errors.Is(err, services.ErrSeasonAlreadyRolledOver) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if err != nil {
+			fmt.Println("Erro na virada de temporada:", err)
+			http.Error(w, "Erro ao realizar a virada de temporada", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(report)
+	}
 }
 
-func addPlayerGameStatsHandler(w http.ResponseWriter, r *http.Request) {
-	var stats models.PlayerGameStats
+func promoteRecruitsHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		var request struct {
+			Year int `json:"year"`
+		}
+		err := json.NewDecoder(r.Body).Decode(&request)
+		if err != nil || request.Year == 0 {
+			http.Error(w, "Ano inválido", http.StatusBadRequest)
+			return
+		}
 
-	err := json.NewDecoder(r.Body).Decode(&stats)
+		report, err := services.PromoteRecruits(request.Year)
+		if err != nil {
+			fmt.Println("Erro ao promover recrutas:", err)
+			http.Error(w, "Erro ao promover recrutas", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(report)
+	default:
+		http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+	}
+}
+
+func depthChartHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		teamID, err := strconv.Atoi(r.URL.Query().Get("team_id"))
+		if err != nil {
+			http.Error(w, "ID do time inválido", http.StatusBadRequest)
+			return
+		}
+		year, err := strconv.Atoi(r.URL.Query().Get("year"))
+		if err != nil {
+			http.Error(w, "Ano inválido", http.StatusBadRequest)
+			return
+		}
+		week, err := strconv.Atoi(r.URL.Query().Get("week"))
+		if err != nil {
+			http.Error(w, "Semana inválida", http.StatusBadRequest)
+			return
+		}
+
+		chart, err := services.GetDepthChart(teamID, year, week)
+		if err != nil {
+			http.Error(w, "Escalação não encontrada", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(chart)
+
+	case http.MethodPost:
+		var chart models.DepthChart
+		err := json.NewDecoder(r.Body).Decode(&chart)
+		if err != nil {
+			http.Error(w, "Erro ao decodificar escalação", http.StatusBadRequest)
+			return
+		}
+
+		validation, err := services.SaveDepthChart(chart)
+		if err != nil {
+			fmt.Println("Erro ao salvar escalação:", err)
+			http.Error(w, "Erro ao salvar escalação", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if len(validation.Errors) > 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(validation)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(validation)
+	}
+}
+
+func depthChartHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	teamID, err := strconv.Atoi(r.URL.Query().Get("team_id"))
 	if err != nil {
-		http.Error(w, "Erro ao decodificar estatísticas do jogo", http.StatusBadRequest)
+		http.Error(w, "ID do time inválido", http.StatusBadRequest)
+		return
+	}
+	year, err := strconv.Atoi(r.URL.Query().Get("year"))
+	if err != nil {
+		http.Error(w, "Ano inválido", http.StatusBadRequest)
 		return
 	}
 
-	err = services.AddPlayerGameStats(stats)
+	history, err := services.GetDepthChartHistory(teamID, year)
 	if err != nil {
-		http.Error(w, "Erro ao adicionar estatísticas do jogo", http.StatusInternalServerError)
+		http.Error(w, "Erro ao obter histórico de escalações", http.StatusInternalServerError)
 		return
 	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
 
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(map[string]string{"message": "Estatísticas do jogo adicionadas com sucesso"})
+func depthChartSlotsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(services.GetDepthChartSlots())
 }
 
-func addRecruitHandler(w http.ResponseWriter, r *http.Request) {
-	var recruit models.Recruit
+func injuriesHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		var playerID, teamID, year int
+		if param := r.URL.Query().Get("player_id"); param != "" {
+			playerID, _ = strconv.Atoi(param)
+		}
+		if param := r.URL.Query().Get("team_id"); param != "" {
+			teamID, _ = strconv.Atoi(param)
+		}
+		if param := r.URL.Query().Get("year"); param != "" {
+			year, _ = strconv.Atoi(param)
+		}
 
-	// Decodificar JSON do corpo da requisição
-	err := json.NewDecoder(r.Body).Decode(&recruit)
+		injuries, err := services.GetInjuriesWithFilters(playerID, teamID, year)
+		if err != nil {
+			http.Error(w, "Erro ao obter lesões", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(injuries)
+
+	case http.MethodPost:
+		var injury models.Injury
+		err := json.NewDecoder(r.Body).Decode(&injury)
+		if err != nil {
+			http.Error(w, "Erro ao decodificar lesão", http.StatusBadRequest)
+			return
+		}
+		err = services.AddInjury(injury)
+		if err != nil {
+			http.Error(w, "Erro ao adicionar lesão", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]string{"message": "Lesão adicionada com sucesso"})
+	}
+}
+
+func injuryHandler(w http.ResponseWriter, r *http.Request) {
+	id := extractID(r.URL.Path)
+	switch r.Method {
+	case http.MethodGet:
+		injury, err := services.GetInjury(id)
+		if err != nil {
+			http.Error(w, "Lesão não encontrada", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(injury)
+
+	case http.MethodPut:
+		var injury models.Injury
+		err := json.NewDecoder(r.Body).Decode(&injury)
+		if err != nil {
+			http.Error(w, "Erro ao decodificar lesão", http.StatusBadRequest)
+			return
+		}
+		injury.InjuryID = id
+		err = services.UpdateInjury(injury)
+		if err != nil {
+			http.Error(w, "Erro ao atualizar lesão", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]string{"message": "Lesão atualizada com sucesso"})
+
+	case http.MethodDelete:
+		err := services.DeleteInjury(id)
+		if err != nil {
+			http.Error(w, "Erro ao excluir lesão", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]string{"message": "Lesão excluída com sucesso"})
+	}
+}
+
+func unavailablePlayersHandler(w http.ResponseWriter, r *http.Request) {
+	teamID, err := strconv.Atoi(r.URL.Query().Get("team_id"))
 	if err != nil {
-		http.Error(w, "Erro ao decodificar dados do recruta", http.StatusBadRequest)
+		http.Error(w, "ID do time inválido", http.StatusBadRequest)
 		return
 	}
-
-	// Inserir recruta na tabela recruits
-	err = services.AddRecruit(recruit)
+	year, err := strconv.Atoi(r.URL.Query().Get("year"))
 	if err != nil {
-		http.Error(w, "Erro ao adicionar recruta", http.StatusInternalServerError)
+		http.Error(w, "Ano inválido", http.StatusBadRequest)
+		return
+	}
+	week, err := strconv.Atoi(r.URL.Query().Get("week"))
+	if err != nil {
+		http.Error(w, "Semana inválida", http.StatusBadRequest)
 		return
 	}
 
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(map[string]string{"message": "Recruta adicionado com sucesso"})
+	players, err := services.GetUnavailablePlayers(teamID, year, week)
+	if err != nil {
+		http.Error(w, "Erro ao obter jogadores indisponíveis", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(players)
 }
 
-func addRecruitedPlayerHandler(w http.ResponseWriter, r *http.Request) {
-	var recruit models.Recruit
+func gamesMissedHandler(w http.ResponseWriter, r *http.Request) {
+	var teamID, playerID, year int
+	if param := r.URL.Query().Get("team_id"); param != "" {
+		teamID, _ = strconv.Atoi(param)
+	}
+	if param := r.URL.Query().Get("player_id"); param != "" {
+		playerID, _ = strconv.Atoi(param)
+	}
+	if param := r.URL.Query().Get("year"); param != "" {
+		year, _ = strconv.Atoi(param)
+	}
 
-	// Decodificar JSON do corpo da requisição
-	err := json.NewDecoder(r.Body).Decode(&recruit)
+	report, err := services.GetGamesMissedReport(teamID, playerID, year)
 	if err != nil {
-		http.Error(w, "Erro ao decodificar dados do recruta", http.StatusBadRequest)
+		http.Error(w, "Erro ao obter relatório de jogos perdidos", http.StatusInternalServerError)
 		return
 	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// devTraitRequest é o corpo das requisições de revelação e upgrade de dev trait
+type devTraitRequest struct {
+	PlayerID int    `json:"player_id"`
+	Year     int    `json:"year"`
+	DevTrait string `json:"dev_trait"`
+}
+
+func devTraitRevealHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		var request devTraitRequest
+		err := json.NewDecoder(r.Body).Decode(&request)
+		if err != nil {
+			http.Error(w, "Erro ao decodificar dev trait", http.StatusBadRequest)
+			return
+		}
 
-	// Chamar a função de serviço para adicionar o recruta
+		err = services.RevealDevTrait(request.PlayerID, request.Year, request.DevTrait)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]string{"message": "Dev trait revelado com sucesso"})
+	}
+}
+
+func devTraitUpgradeHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		var request devTraitRequest
+		err := json.NewDecoder(r.Body).Decode(&request)
+		if err != nil {
+			http.Error(w, "Erro ao decodificar dev trait", http.StatusBadRequest)
+			return
+		}
+
+		err = services.UpgradeDevTrait(request.PlayerID, request.Year, request.DevTrait)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]string{"message": "Dev trait atualizado com sucesso"})
+	}
+}
+
+func devTraitHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	playerID, err := strconv.Atoi(r.URL.Query().Get("player_id"))
+	if err != nil {
+		http.Error(w, "ID do jogador inválido", http.StatusBadRequest)
+		return
+	}
+
+	history, err := services.GetDevTraitHistory(playerID)
+	if err != nil {
+		http.Error(w, "Erro ao obter histórico de dev trait", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
+
+func playerRatingsHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		playerID, err := strconv.Atoi(r.URL.Query().Get("player_id"))
+		if err != nil {
+			http.Error(w, "ID do jogador inválido", http.StatusBadRequest)
+			return
+		}
+
+		ratings, err := services.GetPlayerRatingsHistory(playerID)
+		if err != nil {
+			http.Error(w, "Erro ao obter histórico de ratings", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ratings)
+
+	case http.MethodPost:
+		var rating models.PlayerRating
+		err := json.NewDecoder(r.Body).Decode(&rating)
+		if err != nil {
+			http.Error(w, "Erro ao decodificar rating", http.StatusBadRequest)
+			return
+		}
+		err = services.RecordPlayerRating(rating)
+		if err != nil {
+			http.Error(w, "Erro ao registrar rating", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]string{"message": "Rating registrado com sucesso"})
+	}
+}
+
+func devTraitImpactHandler(w http.ResponseWriter, r *http.Request) {
+	position := r.URL.Query().Get("position")
+
+	report, err := services.GetDevTraitImpactReport(position)
+	if err != nil {
+		http.Error(w, "Erro ao gerar relatório de dev trait", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+func draftHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		var year, teamID int
+		if param := r.URL.Query().Get("year"); param != "" {
+			year, _ = strconv.Atoi(param)
+		}
+		if param := r.URL.Query().Get("team_id"); param != "" {
+			teamID, _ = strconv.Atoi(param)
+		}
+
+		entries, err := services.GetDraftEntries(year, teamID)
+		if err != nil {
+			http.Error(w, "Erro ao obter declarações para o draft", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(entries)
+
+	case http.MethodPost:
+		var entry models.DraftEntry
+		err := json.NewDecoder(r.Body).Decode(&entry)
+		if err != nil {
+			http.Error(w, "Erro ao decodificar declaração para o draft", http.StatusBadRequest)
+			return
+		}
+		err = services.AddDraftDeclaration(entry)
+		if err == sql.ErrNoRows {
+			http.Error(w, "Jogador não encontrado", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, services.ErrAlreadyDeclared) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Erro ao registrar declaração para o draft", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]string{"message": "Declaração para o draft registrada com sucesso"})
+	}
+}
+
+func draftEntryHandler(w http.ResponseWriter, r *http.Request) {
+	id := extractID(r.URL.Path)
+	switch r.Method {
+	case http.MethodGet:
+		entry, err := services.GetDraftEntry(id)
+		if err != nil {
+			http.Error(w, "Declaração para o draft não encontrada", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(entry)
+
+	case http.MethodPut:
+		var entry models.DraftEntry
+		err := json.NewDecoder(r.Body).Decode(&entry)
+		if err != nil {
+			http.Error(w, "Erro ao decodificar resultado do draft", http.StatusBadRequest)
+			return
+		}
+		entry.DraftID = id
+		err = services.UpdateDraftResult(entry)
+		if err == sql.ErrNoRows {
+			http.Error(w, "Declaração não encontrada", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Erro ao atualizar resultado do draft", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]string{"message": "Resultado do draft atualizado com sucesso"})
+
+	case http.MethodDelete:
+		err := services.DeleteDraftEntry(id)
+		if err != nil {
+			http.Error(w, "Erro ao excluir declaração para o draft", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]string{"message": "Declaração para o draft excluída com sucesso"})
+	}
+}
+
+func draftReportHandler(w http.ResponseWriter, r *http.Request) {
+	var teamID int
+	if param := r.URL.Query().Get("team_id"); param != "" {
+		teamID, _ = strconv.Atoi(param)
+	}
+
+	report, err := services.GetDraftReport(teamID)
+	if err != nil {
+		http.Error(w, "Erro ao gerar relatório do draft", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+func departuresHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		var teamID, year int
+		if param := r.URL.Query().Get("team_id"); param != "" {
+			teamID, _ = strconv.Atoi(param)
+		}
+		if param := r.URL.Query().Get("year"); param != "" {
+			year, _ = strconv.Atoi(param)
+		}
+		reason := r.URL.Query().Get("reason")
+
+		departures, err := services.GetDeparturesWithFilters(teamID, year, reason)
+		if err != nil {
+			http.Error(w, "Erro ao obter saídas do elenco", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(departures)
+
+	case http.MethodPost:
+		var departure models.Departure
+		err := json.NewDecoder(r.Body).Decode(&departure)
+		if err != nil {
+			http.Error(w, "Erro ao decodificar saída do elenco", http.StatusBadRequest)
+			return
+		}
+		err = services.AddDeparture(departure)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]string{"message": "Saída registrada com sucesso"})
+	}
+}
+
+func departureHandler(w http.ResponseWriter, r *http.Request) {
+	id := extractID(r.URL.Path)
+	switch r.Method {
+	case http.MethodGet:
+		departure, err := services.GetDeparture(id)
+		if err != nil {
+			http.Error(w, "Saída não encontrada", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(departure)
+
+	case http.MethodPut:
+		var departure models.Departure
+		err := json.NewDecoder(r.Body).Decode(&departure)
+		if err != nil {
+			http.Error(w, "Erro ao decodificar saída do elenco", http.StatusBadRequest)
+			return
+		}
+		departure.DepartureID = id
+		err = services.UpdateDeparture(departure)
+		if err != nil {
+			http.Error(w, "Erro ao atualizar saída", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]string{"message": "Saída atualizada com sucesso"})
+
+	case http.MethodDelete:
+		err := services.DeleteDeparture(id)
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Saída não encontrada", http.StatusNotFound)
+			return
+		}
+		var ruleErr *services.RosterRuleError
+		if errors.Is(err, services.ErrDepartureExhausted) || errors.As(err, &ruleErr) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Erro ao desfazer saída", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]string{"message": "Jogador devolvido ao elenco"})
+	}
+}
+
+func attritionReportHandler(w http.ResponseWriter, r *http.Request) {
+	var teamID int
+	if param := r.URL.Query().Get("team_id"); param != "" {
+		teamID, _ = strconv.Atoi(param)
+	}
+
+	report, err := services.GetAttritionReport(teamID)
+	if err != nil {
+		http.Error(w, "Erro ao gerar relatório de saídas do elenco", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+func awardsHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		awards, err := services.GetAwards()
+		if err != nil {
+			http.Error(w, "Erro ao obter prêmios", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(awards)
+
+	case http.MethodPost:
+		var award models.Award
+		err := json.NewDecoder(r.Body).Decode(&award)
+		if err != nil {
+			http.Error(w, "Erro ao decodificar prêmio", http.StatusBadRequest)
+			return
+		}
+		err = services.AddAward(award)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]string{"message": "Prêmio cadastrado com sucesso"})
+	}
+}
+
+func awardHonorsHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		var year, awardID, playerID, teamID int
+		if param := r.URL.Query().Get("year"); param != "" {
+			year, _ = strconv.Atoi(param)
+		}
+		if param := r.URL.Query().Get("award_id"); param != "" {
+			awardID, _ = strconv.Atoi(param)
+		}
+		if param := r.URL.Query().Get("player_id"); param != "" {
+			playerID, _ = strconv.Atoi(param)
+		}
+		if param := r.URL.Query().Get("team_id"); param != "" {
+			teamID, _ = strconv.Atoi(param)
+		}
+
+		honors, err := services.GetAwardHonorsWithFilters(year, awardID, playerID, teamID)
+		if err != nil {
+			http.Error(w, "Erro ao obter premiados", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(honors)
+
+	case http.MethodPost:
+		var honor models.AwardHonor
+		err := json.NewDecoder(r.Body).Decode(&honor)
+		if err != nil {
+			http.Error(w, "Erro ao decodificar premiado", http.StatusBadRequest)
+			return
+		}
+		err = services.AddAwardHonor(honor)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]string{"message": "Premiação registrada com sucesso"})
+	}
+}
+
+func awardHonorHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		return
+	}
+	err := services.DeleteAwardHonor(extractID(r.URL.Path))
+	if err != nil {
+		http.Error(w, "Erro ao excluir premiação", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Premiação excluída com sucesso"})
+}
+
+func playerTrophyCaseHandler(w http.ResponseWriter, r *http.Request) {
+	playerID, err := strconv.Atoi(r.URL.Query().Get("player_id"))
+	if err != nil {
+		http.Error(w, "player_id inválido", http.StatusBadRequest)
+		return
+	}
+
+	trophyCase, err := services.GetPlayerTrophyCase(playerID)
+	if err != nil {
+		http.Error(w, "Erro ao obter prêmios do jogador", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(trophyCase)
+}
+
+func teamTrophyCaseHandler(w http.ResponseWriter, r *http.Request) {
+	teamID, err := strconv.Atoi(r.URL.Query().Get("team_id"))
+	if err != nil {
+		http.Error(w, "team_id inválido", http.StatusBadRequest)
+		return
+	}
+
+	trophyCase, err := services.GetTeamTrophyCase(teamID)
+	if err != nil {
+		http.Error(w, "Erro ao obter prêmios do time", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(trophyCase)
+}
+
+// parseIDList lê uma lista de IDs separados por vírgula (ex: "1,2,3")
+func parseIDList(param string) ([]int, error) {
+	var ids []int
+	for _, part := range strings.Split(param, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("ID inválido: %q", part)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func playerComparisonHandler(w http.ResponseWriter, r *http.Request) {
+	playerIDs, err := parseIDList(r.URL.Query().Get("player_ids"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	recordIDs, err := parseIDList(r.URL.Query().Get("record_ids"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	comparison, err := services.ComparePlayers(playerIDs, recordIDs, r.URL.Query().Get("align"))
+	if errors.Is(err, services.ErrComparisonTooFew) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Jogador ou recorde não encontrado", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Erro ao comparar jogadores", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(comparison)
+}
+
+func rosterRulesHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		teamID, err := strconv.Atoi(r.URL.Query().Get("team_id"))
+		if err != nil {
+			http.Error(w, "team_id inválido", http.StatusBadRequest)
+			return
+		}
+		rules, err := services.GetRosterRules(teamID)
+		if err != nil {
+			http.Error(w, "Erro ao obter regras de elenco", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(rules)
+
+	case http.MethodPut:
+		var rules models.RosterRules
+		err := json.NewDecoder(r.Body).Decode(&rules)
+		if err != nil {
+			http.Error(w, "Erro ao decodificar regras de elenco", http.StatusBadRequest)
+			return
+		}
+		err = services.SaveRosterRules(rules)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]string{"message": "Regras de elenco salvas com sucesso"})
+	}
+}
+
+func rosterComplianceHandler(w http.ResponseWriter, r *http.Request) {
+	teamID, err := strconv.Atoi(r.URL.Query().Get("team_id"))
+	if err != nil {
+		http.Error(w, "team_id inválido", http.StatusBadRequest)
+		return
+	}
+	year, err := strconv.Atoi(r.URL.Query().Get("year"))
+	if err != nil {
+		http.Error(w, "Ano inválido", http.StatusBadRequest)
+		return
+	}
+
+	compliance, err := services.GetRosterCompliance(teamID, year)
+	if err != nil {
+		http.Error(w, "Erro ao verificar conformidade do elenco", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(compliance)
+}
+
+func personHandler(w http.ResponseWriter, r *http.Request) {
+	if strings.HasSuffix(r.URL.Path, "/split") {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+			return
+		}
+		var split models.PersonSplit
+		err := json.NewDecoder(r.Body).Decode(&split)
+		if err != nil {
+			http.Error(w, "Erro ao decodificar separação", http.StatusBadRequest)
+			return
+		}
+		newID, err := services.SplitPerson(extractID(strings.TrimSuffix(r.URL.Path, "/split")), split)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]interface{}{"message": "Pessoa separada com sucesso", "person_id": newID})
+		return
+	}
+
+	person, err := services.GetPerson(extractID(r.URL.Path))
+	if err != nil {
+		http.Error(w, "Pessoa não encontrada", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(person)
+}
+
+func mergePeopleHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		return
+	}
+	var request struct {
+		TargetID int `json:"target_id"` // Pessoa que permanece
+		SourceID int `json:"source_id"` // Pessoa absorvida e excluída
+	}
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		http.Error(w, "Erro ao decodificar requisição", http.StatusBadRequest)
+		return
+	}
+	err = services.MergePeople(request.TargetID, request.SourceID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Pessoas unidas com sucesso"})
+}
+
+func backfillPeopleHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		return
+	}
+	report, err := services.BackfillPeople()
+	if err != nil {
+		http.Error(w, "Erro ao criar pessoas para registros antigos", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+func searchHandler(w http.ResponseWriter, r *http.Request) {
+	var types []string
+	if param := r.URL.Query().Get("types"); param != "" {
+		types = strings.Split(param, ",")
+	}
+	var limit int
+	if param := r.URL.Query().Get("limit"); param != "" {
+		limit, _ = strconv.Atoi(param)
+	}
+
+	query := r.URL.Query().Get("q")
+	if strings.TrimSpace(query) == "" {
+		http.Error(w, "Informe o texto da busca (q)", http.StatusBadRequest)
+		return
+	}
+
+	hits, err := services.Search(query, types, limit)
+	if err != nil {
+		http.Error(w, "Erro ao executar busca", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(hits)
+}
+
+// teamAndYearParams lê os parâmetros team_id e year, obrigatórios nos relatórios por temporada
+func teamAndYearParams(r *http.Request) (int, int, error) {
+	teamID, err := strconv.Atoi(r.URL.Query().Get("team_id"))
+	if err != nil {
+		return 0, 0, fmt.Errorf("team_id inválido")
+	}
+	year, err := strconv.Atoi(r.URL.Query().Get("year"))
+	if err != nil {
+		return 0, 0, fmt.Errorf("Ano inválido")
+	}
+	return teamID, year, nil
+}
+
+func jerseysHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		teamID, year, err := teamAndYearParams(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		assignments, err := services.GetJerseyNumbers(teamID, year)
+		if err != nil {
+			http.Error(w, "Erro ao obter números de camisa", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(assignments)
+
+	case http.MethodPost:
+		var assignment models.JerseyAssignment
+		err := json.NewDecoder(r.Body).Decode(&assignment)
+		if err != nil {
+			http.Error(w, "Erro ao decodificar número de camisa", http.StatusBadRequest)
+			return
+		}
+		err = services.AssignJerseyNumber(assignment)
+		var ruleErr *services.JerseyRuleError
+		switch {
+		case errors.As(err, &ruleErr):
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		case errors.Is(err, services.ErrJerseyPlayerNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		case err != nil:
+			http.Error(w, "Erro ao definir número", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]string{"message": "Número definido com sucesso"})
+
+	case http.MethodDelete:
+		playerID, err := strconv.Atoi(r.URL.Query().Get("player_id"))
+		if err != nil {
+			http.Error(w, "player_id inválido", http.StatusBadRequest)
+			return
+		}
+		year, err := strconv.Atoi(r.URL.Query().Get("year"))
+		if err != nil {
+			http.Error(w, "Ano inválido", http.StatusBadRequest)
+			return
+		}
+		err = services.RemoveJerseyNumber(playerID, year)
+		if err != nil {
+			http.Error(w, "Erro ao remover número", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]string{"message": "Número removido com sucesso"})
+	}
+}
+
+func jerseySuggestionsHandler(w http.ResponseWriter, r *http.Request) {
+	teamID, year, err := teamAndYearParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	suggestions, err := services.GetJerseySuggestions(teamID, year)
+	if err != nil {
+		http.Error(w, "Erro ao sugerir números", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(suggestions)
+}
+
+func jerseyConflictsHandler(w http.ResponseWriter, r *http.Request) {
+	teamID, year, err := teamAndYearParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	conflicts, err := services.GetJerseyConflicts(teamID, year)
+	if err != nil {
+		http.Error(w, "Erro ao verificar números de camisa", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(conflicts)
+}
+
+func retiredNumbersHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		teamID, err := strconv.Atoi(r.URL.Query().Get("team_id"))
+		if err != nil {
+			http.Error(w, "team_id inválido", http.StatusBadRequest)
+			return
+		}
+		numbers, err := services.GetRetiredNumbers(teamID)
+		if err != nil {
+			http.Error(w, "Erro ao obter números aposentados", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(numbers)
+
+	case http.MethodPost:
+		var retired models.RetiredNumber
+		err := json.NewDecoder(r.Body).Decode(&retired)
+		if err != nil {
+			http.Error(w, "Erro ao decodificar número aposentado", http.StatusBadRequest)
+			return
+		}
+		err = services.AddRetiredNumber(retired)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]string{"message": "Número aposentado com sucesso"})
+
+	case http.MethodDelete:
+		teamID, err := strconv.Atoi(r.URL.Query().Get("team_id"))
+		if err != nil {
+			http.Error(w, "team_id inválido", http.StatusBadRequest)
+			return
+		}
+		number, err := strconv.Atoi(r.URL.Query().Get("number"))
+		if err != nil {
+			http.Error(w, "Número inválido", http.StatusBadRequest)
+			return
+		}
+		err = services.DeleteRetiredNumber(teamID, number)
+		if err != nil {
+			http.Error(w, "Erro ao remover número aposentado", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]string{"message": "Número devolvido ao uso"})
+	}
+}
+
+func recruitingBoardHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		var teamID, year int
+		if param := r.URL.Query().Get("team_id"); param != "" {
+			teamID, _ = strconv.Atoi(param)
+		}
+		if param := r.URL.Query().Get("year"); param != "" {
+			year, _ = strconv.Atoi(param)
+		}
+		page, err := services.GetProspectsWithFilters(teamID, year, r.URL.Query().Get("stage"), parseListOptions(r))
+		if errors.Is(err, services.ErrInvalidSort) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Erro ao obter quadro de recrutamento", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(page)
+
+	case http.MethodPost:
+		var prospect models.Prospect
+		err := json.NewDecoder(r.Body).Decode(&prospect)
+		if err != nil {
+			http.Error(w, "Erro ao decodificar alvo de recrutamento", http.StatusBadRequest)
+			return
+		}
+		err = services.AddProspect(prospect)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]string{"message": "Alvo adicionado ao quadro"})
+
+	default:
+		http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+	}
+}
+
+func prospectHandler(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/weeks"):
+		prospectWeeksHandler(w, r, extractID(strings.TrimSuffix(r.URL.Path, "/weeks")))
+		return
+	case strings.HasSuffix(r.URL.Path, "/visit"):
+		prospectVisitHandler(w, r, extractID(strings.TrimSuffix(r.URL.Path, "/visit")))
+		return
+	case strings.HasSuffix(r.URL.Path, "/sign"):
+		signProspectHandler(w, r, extractID(strings.TrimSuffix(r.URL.Path, "/sign")))
+		return
+	case strings.HasSuffix(r.URL.Path, "/competitors"):
+		prospectCompetitorsHandler(w, r, extractID(strings.TrimSuffix(r.URL.Path, "/competitors")))
+		return
+	}
+
+	id := extractID(r.URL.Path)
+	switch r.Method {
+	case http.MethodGet:
+		prospect, err := services.GetProspect(id)
+		if err != nil {
+			http.Error(w, "Alvo não encontrado", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(prospect)
+
+	case http.MethodPut:
+		var prospect models.Prospect
+		err := json.NewDecoder(r.Body).Decode(&prospect)
+		if err != nil {
+			http.Error(w, "Erro ao decodificar alvo de recrutamento", http.StatusBadRequest)
+			return
+		}
+		prospect.ProspectID = id
+		err = services.UpdateProspect(prospect)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]string{"message": "Alvo atualizado com sucesso"})
+
+	case http.MethodDelete:
+		err := services.DeleteProspect(id)
+		if err != nil {
+			http.Error(w, "Erro ao remover alvo", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]string{"message": "Alvo removido do quadro"})
+
+	default:
+		http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+	}
+}
+
+// prospectWeeksHandler lista o histórico semanal do alvo (GET) ou registra uma semana (POST)
+func prospectWeeksHandler(w http.ResponseWriter, r *http.Request, prospectID int) {
+	switch r.Method {
+	case http.MethodGet:
+		weeks, err := services.GetProspectHistory(prospectID)
+		if err != nil {
+			http.Error(w, "Erro ao obter histórico do alvo", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(weeks)
+
+	case http.MethodPost:
+		var week models.ProspectWeek
+		err := json.NewDecoder(r.Body).Decode(&week)
+		if err != nil {
+			http.Error(w, "Erro ao decodificar semana de recrutamento", http.StatusBadRequest)
+			return
+		}
+		week.ProspectID = prospectID
+		err = services.RecordProspectWeek(week)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]string{"message": "Semana registrada com sucesso"})
+
+	default:
+		http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+	}
+}
+
+// prospectCompetitorsHandler lista (GET) ou substitui (PUT) as escolas que disputam o alvo
+func prospectCompetitorsHandler(w http.ResponseWriter, r *http.Request, prospectID int) {
+	switch r.Method {
+	case http.MethodGet:
+		competitors, err := services.GetProspectCompetitors(prospectID)
+		if err != nil {
+			http.Error(w, "Erro ao obter escolas concorrentes", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(competitors)
+
+	case http.MethodPut:
+		var competitors []models.ProspectCompetitor
+		err := json.NewDecoder(r.Body).Decode(&competitors)
+		if err != nil {
+			http.Error(w, "Erro ao decodificar escolas concorrentes", http.StatusBadRequest)
+			return
+		}
+		err = services.SetProspectCompetitors(prospectID, competitors)
+		if err != nil {
+			prospectError(w, err, "Erro ao atualizar escolas concorrentes")
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]string{"message": "Escolas concorrentes atualizadas com sucesso"})
+
+	default:
+		http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+	}
+}
+
+func recruitingBattlesHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	teamID, err := strconv.Atoi(query.Get("team_id"))
+	if err != nil {
+		http.Error(w, "team_id inválido", http.StatusBadRequest)
+		return
+	}
+	fromYear, _ := strconv.Atoi(query.Get("from_year"))
+	toYear, _ := strconv.Atoi(query.Get("to_year"))
+
+	report, err := services.GetRecruitingBattles(teamID, fromYear, toYear)
+	if err != nil {
+		http.Error(w, "Erro ao gerar relatório de disputas de recrutamento", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+func prospectVisitHandler(w http.ResponseWriter, r *http.Request, prospectID int) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		return
+	}
+	var request struct {
+		ScheduleID int `json:"schedule_id"` // Jogo em casa da visita
+	}
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		http.Error(w, "Erro ao decodificar requisição", http.StatusBadRequest)
+		return
+	}
+	err = services.ScheduleProspectVisit(prospectID, request.ScheduleID)
+	if err != nil {
+		prospectError(w, err, "Erro ao agendar visita")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Visita agendada com sucesso"})
+}
+
+// signProspectHandler recebe os dados do recruta; o que faltar vem do quadro
+func signProspectHandler(w http.ResponseWriter, r *http.Request, prospectID int) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		return
+	}
+	var recruit models.Recruit
+	err := json.NewDecoder(r.Body).Decode(&recruit)
+	if err != nil {
+		http.Error(w, "Erro ao decodificar recruta", http.StatusBadRequest)
+		return
+	}
+	recruitID, err := services.SignProspect(prospectID, recruit)
+	if err != nil {
+		prospectError(w, err, "Erro ao assinar alvo")
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{"message": "Alvo assinado com sucesso", "recruit_id": recruitID})
+}
+
+// prospectError responde aos erros do quadro de recrutamento com o status adequado. Erros do
+// banco ficam no log e voltam com a mensagem genérica
+func prospectError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrProspectNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, services.ErrProspectStage):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, services.ErrInvalidProspect), errors.Is(err, services.ErrInvalidAttributes),
+		errors.Is(err, services.ErrPersonNotFound):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		fmt.Printf("%s: %v\n", message, err)
+		http.Error(w, message, http.StatusInternalServerError)
+	}
+}
+
+func teamConferencesHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		year, err := strconv.Atoi(r.URL.Query().Get("year"))
+		if err != nil {
+			http.Error(w, "Ano inválido", http.StatusBadRequest)
+			return
+		}
+		conferences, err := services.GetTeamConferences(year)
+		if err != nil {
+			http.Error(w, "Erro ao obter conferências", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(conferences)
+
+	case http.MethodPost:
+		var membership models.TeamConference
+		err := json.NewDecoder(r.Body).Decode(&membership)
+		if err != nil {
+			http.Error(w, "Erro ao decodificar conferência", http.StatusBadRequest)
+			return
+		}
+		err = services.SetTeamConference(membership)
+		if errors.Is(err, services.ErrInvalidTeamConference) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err == sql.ErrNoRows {
+			http.Error(w, "Time não encontrado", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Erro ao definir conferência", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]string{"message": "Conferência definida com sucesso"})
+
+	default:
+		http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+	}
+}
+
+func recruitingClassRankingsHandler(w http.ResponseWriter, r *http.Request) {
+	year, err := strconv.Atoi(r.URL.Query().Get("year"))
+	if err != nil {
+		http.Error(w, "Ano inválido", http.StatusBadRequest)
+		return
+	}
+	rankings, err := services.GetRecruitingClassRankings(year, r.URL.Query().Get("conference"))
+	if err != nil {
+		http.Error(w, "Erro ao calcular rankings das classes", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rankings)
+}
+
+func recruitingRetrospectiveHandler(w http.ResponseWriter, r *http.Request) {
+	var teamID, year int
+	if param := r.URL.Query().Get("team_id"); param != "" {
+		teamID, _ = strconv.Atoi(param)
+	}
+	if param := r.URL.Query().Get("year"); param != "" {
+		year, _ = strconv.Atoi(param)
+	}
+	report, err := services.GetRecruitingRetrospective(teamID, year)
+	if err != nil {
+		http.Error(w, "Erro ao gerar retrospectiva de recrutamento", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+func portalHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		var teamID, year int
+		if param := r.URL.Query().Get("team_id"); param != "" {
+			teamID, _ = strconv.Atoi(param)
+		}
+		if param := r.URL.Query().Get("year"); param != "" {
+			year, _ = strconv.Atoi(param)
+		}
+		page, err := services.GetTransfersWithFilters(teamID, year, r.URL.Query().Get("direction"), r.URL.Query().Get("status"),
+			parseListOptions(r))
+		if errors.Is(err, services.ErrInvalidSort) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Erro ao obter transferências", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(page)
+
+	case http.MethodPost:
+		var transfer models.PortalTransfer
+		err := json.NewDecoder(r.Body).Decode(&transfer)
+		if err != nil {
+			http.Error(w, "Erro ao decodificar transferência", http.StatusBadRequest)
+			return
+		}
+
+		// Saídas só precisam do jogador; os demais dados vêm do elenco
+		var id int
+		switch transfer.Direction {
+		case services.TransferIncoming:
+			id, err = services.AddIncomingTransfer(transfer)
+		case services.TransferOutgoing:
+			if transfer.PlayerID == nil {
+				http.Error(w, "Informe o player_id da saída", http.StatusBadRequest)
+				return
+			}
+			id, err = services.AddOutgoingTransfer(*transfer.PlayerID, transfer.Year, transfer.EntryWeek, transfer.Notes)
+		default:
+			http.Error(w, "Direção inválida (use incoming ou outgoing)", http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			portalError(w, err, "Erro ao registrar transferência")
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]interface{}{"message": "Transferência registrada com sucesso", "transfer_id": id})
+
+	default:
+		http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+	}
+}
+
+func portalTransferHandler(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/commit"):
+		if r.Method != http.MethodPost {
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+			return
+		}
+		var request struct {
+			CommitWeek  *int   `json:"commit_week"`
+			Destination string `json:"destination"` // Obrigatório para saídas
+		}
+		err := json.NewDecoder(r.Body).Decode(&request)
+		if err != nil {
+			http.Error(w, "Erro ao decodificar requisição", http.StatusBadRequest)
+			return
+		}
+		err = services.CommitTransfer(extractID(strings.TrimSuffix(r.URL.Path, "/commit")), request.CommitWeek, request.Destination)
+		if err != nil {
+			portalError(w, err, "Erro ao concluir transferência")
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]string{"message": "Transferência concluída com sucesso"})
+		return
+
+	case strings.HasSuffix(r.URL.Path, "/withdraw"):
+		if r.Method != http.MethodPost {
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+			return
+		}
+		err := services.WithdrawTransfer(extractID(strings.TrimSuffix(r.URL.Path, "/withdraw")))
+		if err != nil {
+			portalError(w, err, "Erro ao cancelar transferência")
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]string{"message": "Transferência cancelada"})
+		return
+
+	case strings.HasSuffix(r.URL.Path, "/prior-stats"):
+		if r.Method != http.MethodPut {
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+			return
+		}
+		var stats []models.TransferSeasonStats
+		err := json.NewDecoder(r.Body).Decode(&stats)
+		if err != nil {
+			http.Error(w, "Erro ao decodificar estatísticas", http.StatusBadRequest)
+			return
+		}
+		err = services.ImportTransferPriorStats(extractID(strings.TrimSuffix(r.URL.Path, "/prior-stats")), stats)
+		if err != nil {
+			portalError(w, err, "Erro ao importar estatísticas")
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]string{"message": "Estatísticas importadas com sucesso"})
+		return
+	}
+
+	transfer, err := services.GetTransfer(extractID(r.URL.Path))
+	if err == sql.ErrNoRows {
+		http.Error(w, "Transferência não encontrada", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Erro ao obter transferência", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transfer)
+}
+
+// portalError responde aos erros das movimentações do portal com o status adequado. Erros do
+// banco ficam no log e voltam com a mensagem genérica
+func portalError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrTransferNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "Jogador não encontrado", http.StatusNotFound)
+	case errors.Is(err, services.ErrTransferClosed), errors.Is(err, services.ErrAlreadyInPortal):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, services.ErrInvalidTransfer), errors.Is(err, services.ErrInvalidAttributes),
+		errors.Is(err, services.ErrPersonNotFound):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		fmt.Printf("%s: %v\n", message, err)
+		http.Error(w, message, http.StatusInternalServerError)
+	}
+}
+
+func portalReportHandler(w http.ResponseWriter, r *http.Request) {
+	teamID, year, err := teamAndYearParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	report, err := services.GetPortalReport(teamID, year)
+	if err != nil {
+		http.Error(w, "Erro ao gerar relatório do portal", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// parseGeographyFilter lê os filtros comuns ao relatório e ao mapa de recrutamento
+func parseGeographyFilter(r *http.Request) services.GeographyFilter {
+	query := r.URL.Query()
+	filter := services.GeographyFilter{Source: query.Get("source")}
+	filter.TeamID, _ = strconv.Atoi(query.Get("team_id"))
+	filter.FromYear, _ = strconv.Atoi(query.Get("from_year"))
+	filter.ToYear, _ = strconv.Atoi(query.Get("to_year"))
+	return filter
+}
+
+func recruitingGeographyHandler(w http.ResponseWriter, r *http.Request) {
+	report, err := services.GetRecruitingGeography(parseGeographyFilter(r))
+	if err != nil {
+		http.Error(w, "Erro ao gerar relatório geográfico", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+func recruitingGeoJSONHandler(w http.ResponseWriter, r *http.Request) {
+	collection, err := services.GetRecruitingGeoJSON(parseGeographyFilter(r), r.URL.Query().Get("level"))
+	if err != nil {
+		http.Error(w, "Erro ao gerar mapa de recrutamento", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/geo+json")
+	json.NewEncoder(w).Encode(collection)
+}
+
+func recruitingROIHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := services.RecruitingROIFilter{Position: query.Get("position")}
+	filter.TeamID, _ = strconv.Atoi(query.Get("team_id"))
+	filter.FromYear, _ = strconv.Atoi(query.Get("from_year"))
+	filter.ToYear, _ = strconv.Atoi(query.Get("to_year"))
+
+	report, err := services.GetRecruitingROIReport(filter)
+	if err != nil {
+		http.Error(w, "Erro ao gerar relatório de retorno do recrutamento", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+func rosterCompositionHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		teamID, err := strconv.Atoi(r.URL.Query().Get("team_id"))
+		if err != nil {
+			http.Error(w, "team_id inválido", http.StatusBadRequest)
+			return
+		}
+		composition, err := services.GetRosterComposition(teamID)
+		if err != nil {
+			http.Error(w, "Erro ao obter elenco ideal", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(composition)
+
+	case http.MethodPut:
+		var composition models.RosterComposition
+		err := json.NewDecoder(r.Body).Decode(&composition)
+		if err != nil {
+			http.Error(w, "Erro ao decodificar elenco ideal", http.StatusBadRequest)
+			return
+		}
+		err = services.SaveRosterComposition(composition)
+		if errors.Is(err, services.ErrInvalidRosterComposition) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Erro ao salvar elenco ideal", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]string{"message": "Elenco ideal salvo com sucesso"})
+
+	default:
+		http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+	}
+}
+
+func positionalNeedsHandler(w http.ResponseWriter, r *http.Request) {
+	teamID, year, err := teamAndYearParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	needs, err := services.GetPositionalNeeds(teamID, year)
+	if err != nil {
+		http.Error(w, "Erro ao calcular necessidades por posição", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(needs)
+}
+
+func addPlayerHandler(w http.ResponseWriter, r *http.Request) {
+	var player models.Player
+	// Decodificar o corpo da requisição JSON para a estrutura Player
+	err := json.NewDecoder(r.Body).Decode(&player)
+	if err != nil {
+		http.Error(w, "Erro ao decodificar dados do jogador", http.StatusBadRequest)
+		return
+	}
+
+	// Inserir o jogador no banco de dados (o team_id é resolvido pelo nome do time no serviço)
+	err = services.AddPlayer(player)
+	var ruleErr *services.RosterRuleError
+	if errors.As(err, &ruleErr) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Erro ao adicionar jogador", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Jogador adicionado com sucesso"})
+}
+
+func addPlayerGameStatsHandler(w http.ResponseWriter, r *http.Request) {
+	var stats models.PlayerGameStats
+
+	err := json.NewDecoder(r.Body).Decode(&stats)
+	if err != nil {
+		http.Error(w, "Erro ao decodificar estatísticas do jogo", http.StatusBadRequest)
+		return
+	}
+
+	warnings, err := services.AddPlayerGameStats(stats)
+	if err != nil {
+		http.Error(w, "Erro ao adicionar estatísticas do jogo", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":  "Estatísticas do jogo adicionadas com sucesso",
+		"warnings": warnings,
+	})
+}
+
+// parseListOptions lê a paginação e a ordenação das listagens: page, page_size, sort e order (asc/desc)
+func parseListOptions(r *http.Request) services.ListOptions {
+	query := r.URL.Query()
+	var options services.ListOptions
+	options.Page, _ = strconv.Atoi(query.Get("page"))
+	options.PageSize, _ = strconv.Atoi(query.Get("page_size"))
+	options.SortBy = query.Get("sort")
+	options.Descending = strings.EqualFold(query.Get("order"), "desc")
+	return options
+}
+
+func recruitsHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		query := r.URL.Query()
+		filter := services.RecruitFilter{
+			Position: query.Get("position"),
+			State:    query.Get("state"),
+			DevTrait: query.Get("dev_trait"),
+			Source:   query.Get("source"),
+			GemBust:  query.Get("gem_bust"),
+		}
+		filter.TeamID, _ = strconv.Atoi(query.Get("team_id"))
+		filter.Year, _ = strconv.Atoi(query.Get("year"))
+		filter.Stars, _ = strconv.Atoi(query.Get("stars"))
+
+		page, err := services.GetRecruitsWithFilters(filter, parseListOptions(r))
+		if errors.Is(err, services.ErrInvalidSort) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Erro ao buscar recrutas", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(page)
+
+	case http.MethodPost:
+		addRecruitHandler(w, r)
+
+	default:
+		http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+	}
+}
+
+func recruitHandler(w http.ResponseWriter, r *http.Request) {
+	id := extractID(r.URL.Path)
+	switch r.Method {
+	case http.MethodGet:
+		recruit, err := services.GetRecruit(id)
+		if err == sql.ErrNoRows {
+			http.Error(w, "Recruta não encontrado", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Erro ao obter recruta", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(recruit)
+
+	case http.MethodPut:
+		var recruit models.Recruit
+		err := json.NewDecoder(r.Body).Decode(&recruit)
+		if err != nil {
+			http.Error(w, "Erro ao decodificar dados do recruta", http.StatusBadRequest)
+			return
+		}
+		recruit.RecruitID = id
+		err = services.UpdateRecruit(recruit)
+		if err == sql.ErrNoRows {
+			http.Error(w, "Recruta não encontrado", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, services.ErrInvalidAttributes) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Erro ao atualizar recruta", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]string{"message": "Recruta atualizado com sucesso"})
+
+	case http.MethodDelete:
+		err := services.DeleteRecruit(id)
+		if err == sql.ErrNoRows {
+			http.Error(w, "Recruta não encontrado", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, services.ErrRecruitPromoted) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Erro ao excluir recruta", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]string{"message": "Recruta excluído com sucesso"})
+
+	default:
+		http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+	}
+}
+
+func addRecruitHandler(w http.ResponseWriter, r *http.Request) {
+	var recruit models.Recruit
+
+	// Decodificar JSON do corpo da requisição
+	err := json.NewDecoder(r.Body).Decode(&recruit)
+	if err != nil {
+		http.Error(w, "Erro ao decodificar dados do recruta", http.StatusBadRequest)
+		return
+	}
+
+	// Inserir recruta na tabela recruits
 	err = services.AddRecruit(recruit)
 	if err != nil {
 		http.Error(w, "Erro ao adicionar recruta", http.StatusInternalServerError)
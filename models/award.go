@@ -0,0 +1,22 @@
+package models
+
+type Award struct {
+	AwardID     int    `json:"award_id"`
+	Name        string `json:"name"`     // Ex: Heisman Trophy, All-American, Player of the Week
+	Category    string `json:"category"` // national, all_american, all_conference, weekly
+	Description string `json:"description"`
+}
+
+type AwardHonor struct {
+	HonorID    int    `json:"honor_id"`
+	AwardID    int    `json:"award_id"`
+	AwardName  string `json:"award_name"`
+	Category   string `json:"category"`
+	Year       int    `json:"year"`
+	Week       *int   `json:"week"` // Apenas prêmios semanais
+	PlayerID   *int   `json:"player_id"`
+	PlayerName string `json:"player_name"`
+	TeamID     int    `json:"team_id"`
+	Result     string `json:"result"`    // winner, finalist
+	Selection  string `json:"selection"` // Ex: 1st Team, 2nd Team (All-American / All-Conference)
+}
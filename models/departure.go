@@ -0,0 +1,14 @@
+package models
+
+type Departure struct {
+	DepartureID int    `json:"departure_id"`
+	PlayerID    int    `json:"player_id"`
+	PersonID    *int   `json:"person_id"` // Pessoa do jogador (players.person_id)
+	PlayerName  string `json:"player_name"`
+	Position    string `json:"position"`
+	TeamID      int    `json:"team_id"`
+	Year        int    `json:"year"`        // Temporada após a qual o jogador saiu
+	Reason      string `json:"reason"`      // graduated, declared, transferred, dismissed, medical
+	Destination string `json:"destination"` // Escola de destino em transferências
+	Notes       string `json:"notes"`
+}
@@ -0,0 +1,18 @@
+package models
+
+type DepthChartEntry struct {
+	TeamID     int    `json:"team_id"`
+	Year       int    `json:"year"`
+	Week       int    `json:"week"`  // Semana a partir da qual a escalação vale
+	Slot       string `json:"slot"`  // QB, WR, NCB, KR, PR...
+	Depth      int    `json:"depth"` // 1 = titular, 2 = primeiro reserva...
+	PlayerID   int    `json:"player_id"`
+	PlayerName string `json:"player_name"`
+}
+
+type DepthChart struct {
+	TeamID  int               `json:"team_id"`
+	Year    int               `json:"year"`
+	Week    int               `json:"week"`
+	Entries []DepthChartEntry `json:"entries"`
+}
@@ -0,0 +1,17 @@
+package models
+
+type DraftEntry struct {
+	DraftID          int    `json:"draft_id"`
+	PlayerID         int    `json:"player_id"`
+	PlayerName       string `json:"player_name"`
+	Position         string `json:"position"`
+	TeamID           int    `json:"team_id"`
+	Year             int    `json:"year"`              // Temporada após a qual o jogador se declarou
+	EarlyDeclaration bool   `json:"early_declaration"` // Saiu antes de esgotar a elegibilidade
+	Round            *int   `json:"round"`             // nil = resultado pendente, 0 = não draftado
+	Pick             *int   `json:"pick"`              // Escolha dentro da rodada
+	OverallPick      *int   `json:"overall_pick"`
+	NFLTeam          string `json:"nfl_team"`
+	RecruitID        *int   `json:"recruit_id"` // Recruta de origem
+	Stars            *int   `json:"stars"`      // Estrelas no recrutamento
+}
@@ -26,4 +26,5 @@ type HistoricalRecord struct {
 	YardsFromScrimmage   *int     `json:"yards_from_scrimmage"`
 	AvgYardsPerPlay      *float64 `json:"avg_yards_per_play"`
 	ScrimmageTDs         *int     `json:"scrimmage_tds"`
+	PersonID             *int     `json:"person_id"` // Pessoa vinculada (ver MergePeople)
 }
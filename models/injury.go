@@ -0,0 +1,16 @@
+package models
+
+type Injury struct {
+	InjuryID          int    `json:"injury_id"`
+	PlayerID          int    `json:"player_id"`
+	PlayerName        string `json:"player_name"`
+	Position          string `json:"position"`
+	InjuryType        string `json:"injury_type"` // Ex: Sprain, Fracture, Concussion
+	BodyPart          string `json:"body_part"`   // Ex: Ankle, Knee, Shoulder
+	InjuryDate        string `json:"injury_date"` // Data opcional no formato AAAA-MM-DD
+	Year              int    `json:"year"`        // Temporada da lesão
+	Week              int    `json:"week"`        // Semana da lesão
+	EstimatedWeeksOut int    `json:"estimated_weeks_out"`
+	ReturnYear        *int   `json:"return_year"` // Temporada do retorno real
+	ReturnWeek        *int   `json:"return_week"` // Semana do retorno real
+}
@@ -0,0 +1,18 @@
+package models
+
+type JerseyAssignment struct {
+	PlayerID   int    `json:"player_id"`
+	PlayerName string `json:"player_name"`
+	Position   string `json:"position"`
+	TeamID     int    `json:"team_id"`
+	Year       int    `json:"year"`
+	Number     int    `json:"number"`
+}
+
+type RetiredNumber struct {
+	TeamID      int    `json:"team_id"`
+	Number      int    `json:"number"`
+	PersonID    *int   `json:"person_id"` // Atleta homenageado, quando cadastrado
+	PlayerName  string `json:"player_name"`
+	YearRetired int    `json:"year_retired"`
+}
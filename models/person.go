@@ -0,0 +1,19 @@
+package models
+
+// Person é a identidade estável de um atleta, ligando recruta, jogador, saídas e recordes
+type Person struct {
+	PersonID          int                `json:"person_id"`
+	Name              string             `json:"name"`
+	Recruits          []Recruit          `json:"recruits"`
+	Players           []Player           `json:"players"`
+	Departures        []Departure        `json:"departures"`
+	HistoricalRecords []HistoricalRecord `json:"historical_records"`
+}
+
+// PersonSplit indica os registros que saem de uma pessoa para formar uma nova
+type PersonSplit struct {
+	Name       string `json:"name"` // Nome da nova pessoa; vazio = mantém o da original
+	RecruitIDs []int  `json:"recruit_ids"`
+	PlayerIDs  []int  `json:"player_ids"`
+	RecordIDs  []int  `json:"record_ids"`
+}
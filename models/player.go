@@ -1,18 +1,65 @@
 package models
 
+// ClassYear representa o ano acadêmico do jogador, incluindo as variações redshirt
+type ClassYear string
+
+const (
+	ClassFreshman          ClassYear = "FR"
+	ClassRedshirtFreshman  ClassYear = "RS FR"
+	ClassSophomore         ClassYear = "SO"
+	ClassRedshirtSophomore ClassYear = "RS SO"
+	ClassJunior            ClassYear = "JR"
+	ClassRedshirtJunior    ClassYear = "RS JR"
+	ClassSenior            ClassYear = "SR"
+	ClassRedshirtSenior    ClassYear = "RS SR"
+)
+
 type Player struct {
-	PlayerID          int    `json:"player_id"`
-	Name              string `json:"name"`
-	Position          string `json:"position"`
-	Overall           int    `json:"overall"`
-	GamesPlayed       int    `json:"games_played"`
-	GamesStarted      int    `json:"games_started"`
-	SnapsPlayed       int    `json:"snaps_played"`
-	ClassYear         string `json:"class_year"`       // Freshman, Sophomore, etc.
-	RecruitmentYear   int    `json:"recruitment_year"` // Ano de recrutamento
-	TeamID            int    `json:"team_id"`
-	RecruitmentSource string `json:"recruitment_source"` // Fonte de recrutamento
-	TeamName          string `json:"team_name"`
+	PlayerID             int                `json:"player_id"`
+	Name                 string             `json:"name"`
+	Position             string             `json:"position"`
+	Overall              int                `json:"overall"`
+	GamesPlayed          int                `json:"games_played"`
+	GamesStarted         int                `json:"games_started"`
+	SnapsPlayed          int                `json:"snaps_played"`
+	ClassYear            ClassYear          `json:"class_year"`       // FR, RS FR, SO, RS SO, JR, RS JR, SR, RS SR
+	RecruitmentYear      int                `json:"recruitment_year"` // Ano de recrutamento
+	TeamID               int                `json:"team_id"`
+	RecruitmentSource    string             `json:"recruitment_source"` // Fonte de recrutamento
+	TeamName             string             `json:"team_name"`
+	Redshirted           bool               `json:"redshirted"`              // Já utilizou o ano de redshirt
+	RedshirtYear         *int               `json:"redshirt_year"`           // Temporada em que o redshirt foi utilizado
+	EligibilityExhausted bool               `json:"eligibility_exhausted"`   // Elegibilidade esgotada após a virada de temporada
+	Archetype            string             `json:"archetype"`               // Ex: Pocket Passer, Field General
+	Attributes           map[string]int     `json:"attributes,omitempty"`    // Ex: speed, throw_power, tackling
+	DevTrait             string             `json:"dev_trait"`               // Normal, Impact, Star, Elite
+	DevTraitRevealedYear *int               `json:"dev_trait_revealed_year"` // nil = ainda oculto
+	Active               bool               `json:"active"`                  // false = fora do elenco atual (draft, saída), com histórico preservado
+	RecruitID            *int               `json:"recruit_id"`              // Recruta de origem, quando promovido
+	WalkOn               bool               `json:"walk_on"`                 // Walk-on não ocupa bolsa
+	PersonID             *int               `json:"person_id"`               // Identidade estável entre recruta, jogador e recordes
+	Height               int                `json:"height"`                  // Em polegadas
+	Weight               int                `json:"weight"`                  // Em libras
+	Hometown             string             `json:"hometown"`
+	HomeState            string             `json:"home_state"`
+	Tendency             string             `json:"tendency"`
+	Recruiting           *RecruitingProfile `json:"recruiting,omitempty"` // Dados do recrutamento de origem (somente leitura)
+}
+
+// PlayerRating registra o overall do jogador em uma temporada
+type PlayerRating struct {
+	PlayerID int `json:"player_id"`
+	Year     int `json:"year"`
+	Overall  int `json:"overall"`
+}
+
+// DevTraitEvent registra a linha do tempo do dev trait (assinatura, revelação e upgrades)
+type DevTraitEvent struct {
+	PlayerID      int    `json:"player_id"`
+	Year          int    `json:"year"`
+	Event         string `json:"event"` // signed, revealed, upgraded
+	PreviousTrait string `json:"previous_trait"`
+	DevTrait      string `json:"dev_trait"`
 }
 
 type PlayerGameStats struct {
@@ -0,0 +1,45 @@
+package models
+
+// Prospect é um alvo no quadro de recrutamento, antes de assinar (ver Recruit)
+type Prospect struct {
+	ProspectID      int    `json:"prospect_id"`
+	TeamID          int    `json:"team_id"`
+	Year            int    `json:"year"` // Ciclo de recrutamento (vira recruitment_year ao assinar)
+	Name            string `json:"name"`
+	Position        string `json:"position"`
+	Stars           int    `json:"stars"`
+	NationalRank    int    `json:"national_rank"`
+	PositionRank    int    `json:"position_rank"`
+	Hometown        string `json:"hometown"`
+	HomeState       string `json:"home_state"`
+	Stage           string `json:"stage"`             // scouted, offered, top8, top5, top3, visit_scheduled, committed, signed, lost
+	VisitScheduleID *int   `json:"visit_schedule_id"` // Jogo em casa escolhido para a visita
+	VisitWeek       *int   `json:"visit_week"`
+	RecruitID       *int   `json:"recruit_id"` // Recruta criado ao assinar
+	TotalHours      int    `json:"total_hours"`
+	TotalPoints     int    `json:"total_points"`
+	Notes           string `json:"notes"`
+
+	Competitors []ProspectCompetitor `json:"competitors,omitempty"` // Escolas na disputa pelo alvo
+}
+
+// ProspectCompetitor é uma escola que disputou o alvo com o time
+type ProspectCompetitor struct {
+	ProspectID int    `json:"prospect_id"`
+	TeamID     int    `json:"team_id"`
+	School     string `json:"school"`
+	Standing   *int   `json:"standing"` // Posição final na lista do jogador (1 = escola escolhida)
+	Notes      string `json:"notes"`
+}
+
+// ProspectWeek registra o esforço da semana e a situação do alvo naquele momento
+type ProspectWeek struct {
+	ProspectID   int    `json:"prospect_id"`
+	Year         int    `json:"year"`
+	Week         int    `json:"week"`
+	Stage        string `json:"stage"`
+	Hours        int    `json:"hours"`
+	Points       int    `json:"points"`
+	InterestRank *int   `json:"interest_rank"` // Posição do time na lista do jogador (1 = favorito)
+	Notes        string `json:"notes"`
+}
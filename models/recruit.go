@@ -1,22 +1,43 @@
 package models
 
 type Recruit struct {
+	RecruitID         int            `json:"recruit_id"`
+	PlayerName        string         `json:"player_name"`
+	Class             string         `json:"class"`
+	Position          string         `json:"position"`
+	Tendency          string         `json:"tendency"`
+	PositionRank      int            `json:"position_rank"`
+	NationalRank      int            `json:"national_rank"`
+	Stars             int            `json:"stars"`
+	Hometown          string         `json:"hometown"`
+	HomeState         string         `json:"home_state"`
+	Height            int            `json:"height"`
+	Weight            int            `json:"weight"`
+	DevTrait          string         `json:"dev_trait"`
+	Overall           int            `json:"overall"`
+	GemBust           string         `json:"gem_bust"`
+	RecruitmentSource string         `json:"recruitment_source"` // Fonte de recrutamento: "High School" ou "Transfer Portal"
+	RecruitmentYear   int            `json:"recruitment_year"`   // Ano de recrutamento
+	TeamID            int            `json:"team_id"`            // ID do time
+	Archetype         string         `json:"archetype"`
+	Attributes        map[string]int `json:"attributes,omitempty"`
+	PersonID          *int           `json:"person_id"` // Identidade estável entre recruta, jogador e recordes
+
+	EligibilityRemaining *int `json:"eligibility_remaining"` // Transferências: temporadas restantes informadas no portal
+}
+
+// RecruitingProfile resume o recrutamento de origem de um jogador promovido
+type RecruitingProfile struct {
 	RecruitID         int    `json:"recruit_id"`
-	PlayerName        string `json:"player_name"`
-	Class             string `json:"class"`
-	Position          string `json:"position"`
-	Tendency          string `json:"tendency"`
-	PositionRank      int    `json:"position_rank"`
-	NationalRank      int    `json:"national_rank"`
 	Stars             int    `json:"stars"`
-	Hometown          string `json:"hometown"`
-	HomeState         string `json:"home_state"`
-	Height            int    `json:"height"`
-	Weight            int    `json:"weight"`
-	DevTrait          string `json:"dev_trait"`
-	Overall           int    `json:"overall"`
+	NationalRank      int    `json:"national_rank"`
+	PositionRank      int    `json:"position_rank"`
 	GemBust           string `json:"gem_bust"`
-	RecruitmentSource string `json:"recruitment_source"` // Fonte de recrutamento: "High School" ou "Transfer Portal"
-	RecruitmentYear   int    `json:"recruitment_year"`   // Ano de recrutamento
-	TeamID            int    `json:"team_id"`            // ID do time
+	SigningOverall    int    `json:"signing_overall"`
+	SigningDevTrait   string `json:"signing_dev_trait"`
+	RecruitmentSource string `json:"recruitment_source"`
+	RecruitmentYear   int    `json:"recruitment_year"`
+
+	EligibilityRemaining *int                  `json:"eligibility_remaining,omitempty"` // Chegadas pelo portal
+	PriorStats           []TransferSeasonStats `json:"prior_stats,omitempty"`           // Estatísticas na escola anterior
 }
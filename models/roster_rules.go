@@ -0,0 +1,33 @@
+package models
+
+type PositionLimit struct {
+	Position string `json:"position"`
+	Min      int    `json:"min"` // 0 = sem mínimo
+	Max      int    `json:"max"` // 0 = sem máximo
+}
+
+type ClassLimit struct {
+	ClassYear ClassYear `json:"class_year"`
+	Max       int       `json:"max"`
+}
+
+// RosterRules guarda as regras de elenco da dynasty (uma por time controlado)
+type RosterRules struct {
+	TeamID         int             `json:"team_id"`
+	ScholarshipCap int             `json:"scholarship_cap"` // Jogadores bolsistas (walk-ons não contam)
+	RosterCap      int             `json:"roster_cap"`      // Elenco total; 0 = sem limite
+	PositionLimits []PositionLimit `json:"position_limits"`
+	ClassLimits    []ClassLimit    `json:"class_limits"`
+}
+
+type PositionTarget struct {
+	Position string `json:"position"`
+	Ideal    int    `json:"ideal"` // Jogadores desejados na posição
+}
+
+// RosterComposition é o elenco ideal por posição usado na análise de necessidades do recrutamento
+type RosterComposition struct {
+	TeamID    int              `json:"team_id"`
+	IsDefault bool             `json:"is_default"` // true enquanto o time não configura a sua
+	Positions []PositionTarget `json:"positions"`
+}
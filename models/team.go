@@ -19,3 +19,10 @@ type Team struct {
 	LocationCity    string `json:"location_city"`
 	LocationState   string `json:"location_state"`
 }
+
+// TeamConference registra a conferência do time em uma temporada (permite realinhamentos)
+type TeamConference struct {
+	TeamID     int    `json:"team_id"`
+	Year       int    `json:"year"`
+	Conference string `json:"conference"`
+}
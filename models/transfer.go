@@ -0,0 +1,45 @@
+package models
+
+// PortalTransfer é uma movimentação pelo portal de transferências, de chegada ou de saída
+type PortalTransfer struct {
+	TransferID           int                   `json:"transfer_id"`
+	Direction            string                `json:"direction"` // incoming ou outgoing
+	TeamID               int                   `json:"team_id"`
+	Year                 int                   `json:"year"` // Temporada após a qual o portal foi aberto
+	PlayerName           string                `json:"player_name"`
+	Position             string                `json:"position"`
+	Overall              int                   `json:"overall"`
+	ClassYear            ClassYear             `json:"class_year"`            // Chegadas: ano acadêmico na próxima temporada
+	EligibilityRemaining int                   `json:"eligibility_remaining"` // Temporadas de jogo restantes
+	OriginSchool         string                `json:"origin_school"`         // Escola de origem (chegadas)
+	Destination          string                `json:"destination"`           // Escola de destino (saídas)
+	EntryWeek            *int                  `json:"entry_week"`            // Semana de entrada no portal
+	CommitWeek           *int                  `json:"commit_week"`           // Semana do compromisso com a nova escola
+	Status               string                `json:"status"`                // in_portal, committed, withdrawn
+	PlayerID             *int                  `json:"player_id"`             // Saídas: jogador do elenco
+	RecruitID            *int                  `json:"recruit_id"`            // Chegadas: recruta criado no compromisso
+	PersonID             *int                  `json:"person_id"`
+	Notes                string                `json:"notes"`
+	PriorStats           []TransferSeasonStats `json:"prior_stats,omitempty"`
+}
+
+// TransferSeasonStats guarda as estatísticas de uma temporada na escola anterior
+type TransferSeasonStats struct {
+	Year           int    `json:"year"`
+	School         string `json:"school"`
+	GamesPlayed    int    `json:"games_played"`
+	Completions    int    `json:"completions"`
+	PassAttempts   int    `json:"pass_attempts"`
+	PassingYards   int    `json:"passing_yards"`
+	PassingTDs     int    `json:"passing_tds"`
+	Interceptions  int    `json:"interceptions"`
+	RushAttempts   int    `json:"rush_attempts"`
+	RushingYards   int    `json:"rushing_yards"`
+	RushingTDs     int    `json:"rushing_tds"`
+	Receptions     int    `json:"receptions"`
+	ReceivingYards int    `json:"receiving_yards"`
+	ReceivingTDs   int    `json:"receiving_tds"`
+	Tackles        int    `json:"tackles"`
+	Sacks          int    `json:"sacks"`
+	DefInts        int    `json:"def_interceptions"`
+}
@@ -0,0 +1,236 @@
+package services
+
+import (
+	"dynastyTracker/database"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidAttributes indica arquétipo ou atributos fora do esquema da posição
+var ErrInvalidAttributes = errors.New("atributos inválidos")
+
+// PositionAttributeSchema define os atributos relevantes e os arquétipos de um grupo de posições
+type PositionAttributeSchema struct {
+	Group      string   `json:"group"`
+	Positions  []string `json:"positions"`
+	Attributes []string `json:"attributes"`
+	Archetypes []string `json:"archetypes"`
+}
+
+// AttributeFilter filtra jogadores por faixa de valor de um atributo (0 = sem limite)
+type AttributeFilter struct {
+	Attribute string `json:"attribute"`
+	Min       int    `json:"min"`
+	Max       int    `json:"max"`
+}
+
+// commonAttributes vale para todas as posições
+var commonAttributes = []string{"speed", "acceleration", "agility", "strength", "awareness", "jumping", "stamina", "injury"}
+
+var attributeSchemas = []PositionAttributeSchema{
+	{
+		Group:      "QB",
+		Positions:  []string{"QB"},
+		Attributes: []string{"throw_power", "short_accuracy", "medium_accuracy", "deep_accuracy", "throw_on_the_run", "throw_under_pressure", "play_action", "break_sack", "carrying"},
+		Archetypes: []string{"Pocket Passer", "Field General", "Dual Threat", "Backfield Creator", "Pure Runner"},
+	},
+	{
+		Group:      "HB",
+		Positions:  []string{"HB"},
+		Attributes: []string{"carrying", "break_tackle", "trucking", "stiff_arm", "juke_move", "spin_move", "ball_carrier_vision", "change_of_direction", "catching", "short_route_running", "pass_block"},
+		Archetypes: []string{"Elusive Bruiser", "Contact Seeker", "Backfield Threat", "North/South Receiver", "North/South Blocker", "East/West Playmaker"},
+	},
+	{
+		Group:      "FB",
+		Positions:  []string{"FB"},
+		Attributes: []string{"run_block", "lead_block", "impact_blocking", "pass_block", "carrying", "trucking", "catching"},
+		Archetypes: []string{"Blocking", "Utility"},
+	},
+	{
+		Group:      "WR",
+		Positions:  []string{"WR"},
+		Attributes: []string{"catching", "catch_in_traffic", "spectacular_catch", "short_route_running", "medium_route_running", "deep_route_running", "release", "change_of_direction", "carrying"},
+		Archetypes: []string{"Contested Specialist", "Elusive Route Runner", "Gadget", "Gritty Possession", "Physical Route Runner", "Route Artist", "Speedster"},
+	},
+	{
+		Group:      "TE",
+		Positions:  []string{"TE"},
+		Attributes: []string{"catching", "catch_in_traffic", "short_route_running", "medium_route_running", "deep_route_running", "release", "run_block", "pass_block", "lead_block"},
+		Archetypes: []string{"Gritty Possession", "Physical Route Runner", "Possession", "Pure Blocker", "Vertical Threat"},
+	},
+	{
+		Group:      "OL",
+		Positions:  []string{"LT", "LG", "C", "RG", "RT"},
+		Attributes: []string{"run_block", "run_block_power", "run_block_finesse", "pass_block", "pass_block_power", "pass_block_finesse", "impact_blocking", "lead_block"},
+		Archetypes: []string{"Agile", "Pass Protector", "Raw Strength", "Well Rounded"},
+	},
+	{
+		Group:      "DL",
+		Positions:  []string{"LEDG", "REDG", "DT"},
+		Attributes: []string{"tackling", "hit_power", "power_moves", "finesse_moves", "block_shedding", "pursuit", "play_recognition"},
+		Archetypes: []string{"Edge Setter", "Gap Specialist", "Power Rusher", "Pure Power", "Speed Rusher"},
+	},
+	{
+		Group:      "LB",
+		Positions:  []string{"SAM", "MIKE", "WILL"},
+		Attributes: []string{"tackling", "hit_power", "pursuit", "play_recognition", "block_shedding", "power_moves", "finesse_moves", "zone_coverage", "man_coverage"},
+		Archetypes: []string{"Lurker", "Signal Caller", "Thumper"},
+	},
+	{
+		Group:      "CB",
+		Positions:  []string{"CB"},
+		Attributes: []string{"man_coverage", "zone_coverage", "press", "play_recognition", "catching", "tackling", "hit_power", "pursuit", "return"},
+		Archetypes: []string{"Boundary", "Bump and Run", "Field", "Zone"},
+	},
+	{
+		Group:      "S",
+		Positions:  []string{"FS", "SS"},
+		Attributes: []string{"man_coverage", "zone_coverage", "press", "play_recognition", "catching", "tackling", "hit_power", "pursuit", "return"},
+		Archetypes: []string{"Box Specialist", "Coverage Specialist", "Hybrid"},
+	},
+	{
+		Group:      "K",
+		Positions:  []string{"K", "P"},
+		Attributes: []string{"kick_power", "kick_accuracy"},
+		Archetypes: []string{"Accurate", "Power"},
+	},
+}
+
+// GetAttributeSchemas retorna o esquema de atributos de todas as posições
+func GetAttributeSchemas() []PositionAttributeSchema {
+	schemas := make([]PositionAttributeSchema, 0, len(attributeSchemas))
+	for _, schema := range attributeSchemas {
+		schema.Attributes = append(append([]string{}, commonAttributes...), schema.Attributes...)
+		schemas = append(schemas, schema)
+	}
+	return schemas
+}
+
+// GetAttributeSchema retorna o esquema completo (comuns + específicos) da posição
+func GetAttributeSchema(position string) (PositionAttributeSchema, bool) {
+	for _, schema := range GetAttributeSchemas() {
+		for _, schemaPosition := range schema.Positions {
+			if schemaPosition == position {
+				return schema, true
+			}
+		}
+	}
+	return PositionAttributeSchema{}, false
+}
+
+// IsKnownAttribute indica se o atributo existe em alguma posição
+func IsKnownAttribute(attribute string) bool {
+	for _, schema := range GetAttributeSchemas() {
+		for _, known := range schema.Attributes {
+			if known == attribute {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ValidateAttributes confere arquétipo e atributos contra o esquema da posição
+func ValidateAttributes(position string, archetype string, attributes map[string]int) error {
+	schema, ok := GetAttributeSchema(position)
+	if !ok {
+		if archetype != "" || len(attributes) > 0 {
+			return fmt.Errorf("%w: posição sem esquema de atributos: %s", ErrInvalidAttributes, position)
+		}
+		return nil
+	}
+
+	if archetype != "" {
+		valid := false
+		for _, known := range schema.Archetypes {
+			if strings.EqualFold(known, archetype) {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return fmt.Errorf("%w: arquétipo %q inválido para a posição %s", ErrInvalidAttributes, archetype, position)
+		}
+	}
+
+	allowed := make(map[string]bool)
+	for _, attribute := range schema.Attributes {
+		allowed[attribute] = true
+	}
+	for attribute, value := range attributes {
+		if !allowed[attribute] {
+			return fmt.Errorf("%w: atributo %q não se aplica à posição %s", ErrInvalidAttributes, attribute, position)
+		}
+		if value < 0 || value > 99 {
+			return fmt.Errorf("%w: valor inválido para %s: %d", ErrInvalidAttributes, attribute, value)
+		}
+	}
+	return nil
+}
+
+// saveAttributes substitui os atributos de um jogador ou recruta (table: player_attributes ou recruit_attributes)
+func saveAttributes(table string, idColumn string, id int, attributes map[string]int) error {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := replaceAttributes(tx, table, idColumn, id, attributes); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// replaceAttributes substitui os atributos dentro da transação do chamador
+func replaceAttributes(exec sqlExecer, table string, idColumn string, id int, attributes map[string]int) error {
+	_, err := exec.Exec(fmt.Sprintf("DELETE FROM %s WHERE %s = ?", table, idColumn), id)
+	if err != nil {
+		fmt.Printf("Erro ao limpar atributos: %v\n", err)
+		return err
+	}
+	for attribute, value := range attributes {
+		_, err = exec.Exec(fmt.Sprintf("INSERT INTO %s (%s, attribute, value) VALUES (?, ?, ?)", table, idColumn), id, attribute, value)
+		if err != nil {
+			fmt.Printf("Erro ao gravar atributo: %v\n", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// loadAttributes busca os atributos de vários jogadores ou recrutas de uma vez
+func loadAttributes(table string, idColumn string, ids []int) (map[int]map[string]int, error) {
+	attributes := make(map[int]map[string]int)
+	if len(ids) == 0 {
+		return attributes, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+
+	rows, err := database.DB.Query(fmt.Sprintf("SELECT %s, attribute, value FROM %s WHERE %s IN (%s)",
+		idColumn, table, idColumn, placeholders), args...)
+	if err != nil {
+		fmt.Printf("Erro ao buscar atributos: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, value int
+		var attribute string
+		if err := rows.Scan(&id, &attribute, &value); err != nil {
+			return nil, err
+		}
+		if attributes[id] == nil {
+			attributes[id] = make(map[string]int)
+		}
+		attributes[id][attribute] = value
+	}
+	return attributes, nil
+}
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"dynastyTracker/database"
+	"dynastyTracker/models"
+	"fmt"
+)
+
+var awardCategories = map[string]bool{
+	"national":       true,
+	"all_american":   true,
+	"all_conference": true,
+	"weekly":         true,
+}
+
+// AddAward cadastra a definição de um prêmio
+func AddAward(award models.Award) error {
+	if !awardCategories[award.Category] {
+		return fmt.Errorf("categoria de prêmio inválida: %q", award.Category)
+	}
+	_, err := database.DB.Exec("INSERT INTO awards (name, category, description) VALUES (?, ?, ?)",
+		award.Name, award.Category, award.Description)
+	if err != nil {
+		fmt.Printf("Erro ao adicionar prêmio: %v\n", err)
+		return err
+	}
+	return nil
+}
+
+// GetAwards lista as definições de prêmios
+func GetAwards() ([]models.Award, error) {
+	rows, err := database.DB.Query("SELECT award_id, name, category, COALESCE(description, '') FROM awards ORDER BY category, name")
+	if err != nil {
+		fmt.Printf("Erro ao executar consulta: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var awards []models.Award
+	for rows.Next() {
+		var award models.Award
+		if err := rows.Scan(&award.AwardID, &award.Name, &award.Category, &award.Description); err != nil {
+			fmt.Printf("Erro ao escanear resultados: %v\n", err)
+			return nil, err
+		}
+		awards = append(awards, award)
+	}
+	return awards, nil
+}
+
+// AddAwardHonor registra um vencedor ou finalista de um prêmio na temporada
+func AddAwardHonor(honor models.AwardHonor) error {
+	if honor.Result != "winner" && honor.Result != "finalist" {
+		return fmt.Errorf("resultado inválido: %q (use winner ou finalist)", honor.Result)
+	}
+
+	var category string
+	err := database.DB.QueryRow("SELECT category FROM awards WHERE award_id = ?", honor.AwardID).Scan(&category)
+	if err != nil {
+		return fmt.Errorf("prêmio não encontrado: %d", honor.AwardID)
+	}
+	if category == "weekly" && honor.Week == nil {
+		return fmt.Errorf("prêmios semanais precisam da semana")
+	}
+
+	_, err = database.DB.Exec(`
+        INSERT INTO award_honors (award_id, year, week, player_id, player_name, team_id, result, selection)
+        VALUES (?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''))
+    `, honor.AwardID, honor.Year, honor.Week, honor.PlayerID, honor.PlayerName, honor.TeamID, honor.Result, honor.Selection)
+	if err != nil {
+		fmt.Printf("Erro ao registrar prêmio: %v\n", err)
+		return err
+	}
+	return nil
+}
+
+// DeleteAwardHonor exclui um vencedor ou finalista registrado
+func DeleteAwardHonor(id int) error {
+	_, err := database.DB.Exec("DELETE FROM award_honors WHERE honor_id = ?", id)
+	return err
+}
+
+// GetAwardHonorsWithFilters lista vencedores e finalistas por temporada, prêmio, jogador e time
+func GetAwardHonorsWithFilters(year int, awardID int, playerID int, teamID int) ([]models.AwardHonor, error) {
+	query := `
+        SELECT h.honor_id, h.award_id, a.name, a.category, h.year, h.week, h.player_id,
+               COALESCE(p.name, h.player_name, ''), h.team_id, h.result, COALESCE(h.selection, '')
+        FROM award_honors h
+        JOIN awards a ON a.award_id = h.award_id
+        LEFT JOIN players p ON p.player_id = h.player_id
+        WHERE 1=1`
+	var args []interface{}
+	if year > 0 {
+		query += " AND h.year = ?"
+		args = append(args, year)
+	}
+	if awardID > 0 {
+		query += " AND h.award_id = ?"
+		args = append(args, awardID)
+	}
+	if playerID > 0 {
+		query += " AND h.player_id = ?"
+		args = append(args, playerID)
+	}
+	if teamID > 0 {
+		query += " AND h.team_id = ?"
+		args = append(args, teamID)
+	}
+	query += " ORDER BY h.year, FIELD(a.category, 'national', 'all_american', 'all_conference', 'weekly'), a.name, h.week, h.result DESC"
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		fmt.Printf("Erro ao executar consulta: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var honors []models.AwardHonor
+	for rows.Next() {
+		var honor models.AwardHonor
+		err := rows.Scan(&honor.HonorID, &honor.AwardID, &honor.AwardName, &honor.Category, &honor.Year, &honor.Week,
+			&honor.PlayerID, &honor.PlayerName, &honor.TeamID, &honor.Result, &honor.Selection)
+		if err != nil {
+			fmt.Printf("Erro ao escanear resultados: %v\n", err)
+			return nil, err
+		}
+		honors = append(honors, honor)
+	}
+	return honors, nil
+}
+
+type TrophyCase struct {
+	PlayerID   int                 `json:"player_id,omitempty"`
+	TeamID     int                 `json:"team_id,omitempty"`
+	Wins       int                 `json:"wins"`
+	Finalists  int                 `json:"finalists"`
+	ByCategory map[string]int      `json:"by_category"` // Vitórias por categoria
+	Honors     []models.AwardHonor `json:"honors"`
+}
+
+func buildTrophyCase(honors []models.AwardHonor) TrophyCase {
+	trophyCase := TrophyCase{ByCategory: make(map[string]int), Honors: honors}
+	for _, honor := range honors {
+		if honor.Result == "winner" {
+			trophyCase.Wins++
+			trophyCase.ByCategory[honor.Category]++
+		} else {
+			trophyCase.Finalists++
+		}
+	}
+	return trophyCase
+}
+
+// GetPlayerTrophyCase reúne todos os prêmios e finais de um jogador
+func GetPlayerTrophyCase(playerID int) (TrophyCase, error) {
+	honors, err := GetAwardHonorsWithFilters(0, 0, playerID, 0)
+	if err != nil {
+		return TrophyCase{}, err
+	}
+	trophyCase := buildTrophyCase(honors)
+	trophyCase.PlayerID = playerID
+	return trophyCase, nil
+}
+
+// GetTeamTrophyCase reúne todos os prêmios conquistados por jogadores do time
+func GetTeamTrophyCase(teamID int) (TrophyCase, error) {
+	honors, err := GetAwardHonorsWithFilters(0, 0, 0, teamID)
+	if err != nil {
+		return TrophyCase{}, err
+	}
+	trophyCase := buildTrophyCase(honors)
+	trophyCase.TeamID = teamID
+	return trophyCase, nil
+}
+
+// getPlayerAwardCountsByYear conta vitórias e finais do jogador por temporada
+func getPlayerAwardCountsByYear(playerID int) (map[int][2]int, error) {
+	rows, err := database.DB.Query(`
+        SELECT year,
+            SUM(CASE WHEN result = 'winner' THEN 1 ELSE 0 END) AS wins,
+            SUM(CASE WHEN result = 'finalist' THEN 1 ELSE 0 END) AS finalists
+        FROM award_honors
+        WHERE player_id = ?
+        GROUP BY year
+    `, playerID)
+	if err != nil {
+		fmt.Printf("Erro ao executar consulta: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[int][2]int)
+	for rows.Next() {
+		var year, wins, finalists int
+		if err := rows.Scan(&year, &wins, &finalists); err != nil {
+			return nil, err
+		}
+		counts[year] = [2]int{wins, finalists}
+	}
+	return counts, nil
+}
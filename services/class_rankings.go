@@ -0,0 +1,211 @@
+package services
+
+import (
+	"dynastyTracker/database"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Pesos de cada componente na nota do recruta. Componentes ausentes (ranking 0, overall 0)
+// são ignorados e os pesos restantes são redistribuídos
+const (
+	weightStars        = 0.35
+	weightNationalRank = 0.30
+	weightPositionRank = 0.15
+	weightOverall      = 0.20
+)
+
+// Profundidade da classe: o n-ésimo recruta vale e^(-((n-1)/λ)²) do seu rating acima da base,
+// como nos rankings de classe dos sites de recrutamento. Com λ = 15, o 10º vale ~70% e o 25º ~8%
+const (
+	classDepthLambda = 15.0
+	ratingBaseline   = 70.0
+)
+
+var starRatings = map[int]float64{5: 98, 4: 92, 3: 85, 2: 78, 1: 72}
+
+// recruitRating combina estrelas, rankings e overall em uma nota de 70 a 100
+func recruitRating(stars int, nationalRank int, positionRank int, overall int) float64 {
+	var total, weights float64
+	add := func(value float64, weight float64) {
+		total += math.Max(ratingBaseline, math.Min(100, value)) * weight
+		weights += weight
+	}
+
+	if rating, ok := starRatings[stars]; ok {
+		add(rating, weightStars)
+	}
+	if nationalRank > 0 {
+		add(100-10*math.Log10(float64(nationalRank)), weightNationalRank) // 1º = 100, 100º = 80, 1000º = 70
+	}
+	if positionRank > 0 {
+		add(100-12*math.Log10(float64(positionRank)), weightPositionRank) // 1º = 100, 10º = 88
+	}
+	if overall > 0 {
+		add(ratingBaseline+float64(overall-50)*0.6, weightOverall) // 50 = 70, 80 = 88
+	}
+
+	if weights == 0 {
+		return ratingBaseline
+	}
+	return total / weights
+}
+
+type ClassPositionBreakdown struct {
+	Position      string  `json:"position"`
+	Commits       int     `json:"commits"`
+	AverageStars  float64 `json:"average_stars"`
+	AverageRating float64 `json:"average_rating"`
+	Points        float64 `json:"points"` // Contribuição da posição para a nota da classe
+}
+
+type RecruitingClassRanking struct {
+	TeamID         int                      `json:"team_id"`
+	School         string                   `json:"school"`
+	Conference     string                   `json:"conference"`
+	Year           int                      `json:"year"`
+	Commits        int                      `json:"commits"`
+	Score          float64                  `json:"score"`
+	NationalRank   int                      `json:"national_rank"`
+	ConferenceRank int                      `json:"conference_rank"` // 0 quando o time não tem conferência cadastrada
+	AverageStars   float64                  `json:"average_stars"`
+	AverageRating  float64                  `json:"average_rating"`
+	StarCounts     map[int]int              `json:"star_counts"`
+	Positions      []ClassPositionBreakdown `json:"positions"`
+}
+
+type rankedRecruit struct {
+	teamID   int
+	position string
+	stars    int
+	rating   float64
+}
+
+// GetRecruitingClassRankings calcula a nota da classe de cada time no ano de recrutamento e
+// ordena nacionalmente e por conferência. conference limita a resposta a uma conferência
+func GetRecruitingClassRankings(year int, conference string) ([]RecruitingClassRanking, error) {
+	rows, err := database.DB.Query(`
+        SELECT r.team_id, r.position, r.stars, r.national_rank, r.position_rank, r.overall
+        FROM recruits r
+        WHERE r.recruitment_year = ?
+    `, year)
+	if err != nil {
+		fmt.Printf("Erro ao executar consulta: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	byTeam := make(map[int][]rankedRecruit)
+	for rows.Next() {
+		var r rankedRecruit
+		var nationalRank, positionRank, overall int
+		if err := rows.Scan(&r.teamID, &r.position, &r.stars, &nationalRank, &positionRank, &overall); err != nil {
+			fmt.Printf("Erro ao escanear resultados: %v\n", err)
+			return nil, err
+		}
+		r.rating = recruitRating(r.stars, nationalRank, positionRank, overall)
+		byTeam[r.teamID] = append(byTeam[r.teamID], r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	schools, err := getTeamSchools()
+	if err != nil {
+		return nil, err
+	}
+	conferences, err := GetTeamConferences(year)
+	if err != nil {
+		return nil, err
+	}
+
+	var rankings []RecruitingClassRanking
+	for teamID, recruits := range byTeam {
+		rankings = append(rankings, scoreRecruitingClass(teamID, year, recruits, schools[teamID], conferences[teamID]))
+	}
+
+	sort.Slice(rankings, func(i, j int) bool {
+		if rankings[i].Score != rankings[j].Score {
+			return rankings[i].Score > rankings[j].Score
+		}
+		return rankings[i].School < rankings[j].School
+	})
+	conferencePosition := make(map[string]int)
+	var filtered []RecruitingClassRanking
+	for i := range rankings {
+		rankings[i].NationalRank = i + 1
+		if rankings[i].Conference != "" {
+			conferencePosition[rankings[i].Conference]++
+			rankings[i].ConferenceRank = conferencePosition[rankings[i].Conference]
+		}
+		if conference == "" || rankings[i].Conference == conference {
+			filtered = append(filtered, rankings[i])
+		}
+	}
+	return filtered, nil
+}
+
+// scoreRecruitingClass soma os recrutas do melhor para o pior com peso decrescente pela profundidade
+func scoreRecruitingClass(teamID int, year int, recruits []rankedRecruit, school string, conference string) RecruitingClassRanking {
+	sort.Slice(recruits, func(i, j int) bool { return recruits[i].rating > recruits[j].rating })
+
+	ranking := RecruitingClassRanking{TeamID: teamID, School: school, Conference: conference, Year: year,
+		Commits: len(recruits), StarCounts: make(map[int]int)}
+	positions := make(map[string]*ClassPositionBreakdown)
+	var order []string
+	var stars, ratings float64
+
+	for n, r := range recruits {
+		points := (r.rating - ratingBaseline) * math.Exp(-math.Pow(float64(n)/classDepthLambda, 2))
+		ranking.Score += points
+		ranking.StarCounts[r.stars]++
+		stars += float64(r.stars)
+		ratings += r.rating
+
+		breakdown, ok := positions[r.position]
+		if !ok {
+			breakdown = &ClassPositionBreakdown{Position: r.position}
+			positions[r.position] = breakdown
+			order = append(order, r.position)
+		}
+		breakdown.Commits++
+		breakdown.AverageStars += float64(r.stars)
+		breakdown.AverageRating += r.rating
+		breakdown.Points += points
+	}
+
+	ranking.AverageStars = stars / float64(len(recruits))
+	ranking.AverageRating = ratings / float64(len(recruits))
+	for _, position := range order {
+		breakdown := positions[position]
+		breakdown.AverageStars /= float64(breakdown.Commits)
+		breakdown.AverageRating /= float64(breakdown.Commits)
+		ranking.Positions = append(ranking.Positions, *breakdown)
+	}
+	sort.SliceStable(ranking.Positions, func(i, j int) bool { return ranking.Positions[i].Points > ranking.Positions[j].Points })
+	return ranking
+}
+
+func getTeamSchools() (map[int]string, error) {
+	rows, err := database.DB.Query("SELECT team_id, school FROM teams")
+	if err != nil {
+		fmt.Printf("Erro ao buscar times: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	schools := make(map[int]string)
+	for rows.Next() {
+		var teamID int
+		var school string
+		if err := rows.Scan(&teamID, &school); err != nil {
+			return nil, err
+		}
+		schools[teamID] = school
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return schools, nil
+}
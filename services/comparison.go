@@ -0,0 +1,263 @@
+package services
+
+import (
+	"dynastyTracker/database"
+	"dynastyTracker/models"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var ErrComparisonTooFew = errors.New("informe ao menos dois jogadores ou recordes para comparar")
+
+type CareerRates struct {
+	PassingYards   float64 `json:"passing_yards"`
+	PassingTDs     float64 `json:"passing_tds"`
+	RushingYards   float64 `json:"rushing_yards"`
+	RushingTDs     float64 `json:"rushing_tds"`
+	ReceivingYards float64 `json:"receiving_yards"`
+	ReceivingTDs   float64 `json:"receiving_tds"`
+}
+
+type ComparisonSeason struct {
+	SeasonNumber int `json:"season_number"` // 1 = primeira temporada com jogos; conta anos desde então
+	PlayerYearlyStats
+}
+
+type ComparisonSubject struct {
+	Key         string                `json:"key"` // "player:<id>" ou "record:<id>"
+	PlayerID    int                   `json:"player_id,omitempty"`
+	RecordID    int                   `json:"record_id,omitempty"`
+	Name        string                `json:"name"`
+	Position    string                `json:"position,omitempty"`
+	School      string                `json:"school,omitempty"`
+	Active      bool                  `json:"active"`
+	Career      PlayerCareerTotals    `json:"career"`
+	PerGame     *CareerRates          `json:"per_game"` // nil quando não há jogos registrados (recordes históricos)
+	PerSeason   *CareerRates          `json:"per_season"`
+	Ratings     []models.PlayerRating `json:"ratings,omitempty"`
+	Seasons     []ComparisonSeason    `json:"seasons,omitempty"`
+	Percentiles map[string]float64    `json:"percentiles,omitempty"` // Percentil na posição, entre todos os jogadores da dynasty
+}
+
+type AlignedSeason struct {
+	Key     int                 `json:"key"`     // Número da temporada ou ano, conforme o alinhamento
+	Entries []*ComparisonSeason `json:"entries"` // Um por jogador, na ordem de subjects; nil = sem temporada
+}
+
+type PlayerComparison struct {
+	Alignment string              `json:"alignment"` // "season" ou "year"
+	Subjects  []ComparisonSubject `json:"subjects"`
+	Seasons   []AlignedSeason     `json:"seasons"`
+}
+
+func careerRates(career PlayerCareerTotals, divisor int) *CareerRates {
+	if divisor == 0 {
+		return nil
+	}
+	d := float64(divisor)
+	return &CareerRates{
+		PassingYards:   float64(career.PassingYards) / d,
+		PassingTDs:     float64(career.PassingTDs) / d,
+		RushingYards:   float64(career.RushingYards) / d,
+		RushingTDs:     float64(career.RushingTDs) / d,
+		ReceivingYards: float64(career.ReceivingYards) / d,
+		ReceivingTDs:   float64(career.ReceivingTDs) / d,
+	}
+}
+
+func intValue(value *int) int {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
+
+// getPositionCareerTotals soma a carreira de todos os jogadores da posição, atuais e que já saíram
+func getPositionCareerTotals(position string) ([]PlayerCareerTotals, error) {
+	rows, err := database.DB.Query(`
+        SELECT
+            COALESCE(SUM(g.completions), 0), COALESCE(SUM(g.passing_yards), 0), COALESCE(SUM(g.passing_tds), 0),
+            COALESCE(SUM(g.rushing_yards), 0), COALESCE(SUM(g.rushing_tds), 0),
+            COALESCE(SUM(g.receiving_yards), 0), COALESCE(SUM(g.receiving_tds), 0)
+        FROM players p
+        LEFT JOIN playergamestats g ON g.player_id = p.player_id
+        WHERE p.position = ?
+        GROUP BY `+personKey+`
+    `, position)
+	if err != nil {
+		fmt.Printf("Erro ao executar consulta: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var totals []PlayerCareerTotals
+	for rows.Next() {
+		var career PlayerCareerTotals
+		err := rows.Scan(&career.Completions, &career.PassingYards, &career.PassingTDs,
+			&career.RushingYards, &career.RushingTDs, &career.ReceivingYards, &career.ReceivingTDs)
+		if err != nil {
+			fmt.Printf("Erro ao escanear resultados: %v\n", err)
+			return nil, err
+		}
+		totals = append(totals, career)
+	}
+	return totals, nil
+}
+
+// percentileRanks calcula, para cada categoria, a porcentagem de jogadores da posição
+// com total de carreira menor ou igual ao do jogador
+func percentileRanks(career PlayerCareerTotals, population []PlayerCareerTotals) map[string]float64 {
+	if len(population) == 0 {
+		return nil
+	}
+	values := func(c PlayerCareerTotals) map[string]int {
+		return map[string]int{
+			"completions":     c.Completions,
+			"passing_yards":   c.PassingYards,
+			"passing_tds":     c.PassingTDs,
+			"rushing_yards":   c.RushingYards,
+			"rushing_tds":     c.RushingTDs,
+			"receiving_yards": c.ReceivingYards,
+			"receiving_tds":   c.ReceivingTDs,
+		}
+	}
+
+	player := values(career)
+	below := make(map[string]int)
+	for _, other := range population {
+		for category, value := range values(other) {
+			if value <= player[category] {
+				below[category]++
+			}
+		}
+	}
+
+	percentiles := make(map[string]float64)
+	for category := range player {
+		percentiles[category] = float64(below[category]) / float64(len(population)) * 100
+	}
+	return percentiles
+}
+
+func comparePlayer(playerID int, positionTotals map[string][]PlayerCareerTotals) (ComparisonSubject, error) {
+	subject := ComparisonSubject{Key: fmt.Sprintf("player:%d", playerID), PlayerID: playerID}
+
+	player, err := GetPlayer(playerID)
+	if err != nil {
+		return subject, err
+	}
+	subject.Name, subject.Position, subject.Active = player.Name, player.Position, player.Active
+
+	seasons, err := GetPlayerCareerProgression(playerID)
+	if err != nil {
+		return subject, err
+	}
+	gameLog, err := GetPlayerGameLog(playerID, 0, "")
+	if err != nil {
+		return subject, err
+	}
+	if subject.Ratings, err = GetPlayerRatingsHistory(playerID); err != nil {
+		return subject, err
+	}
+
+	subject.Career = sumCareerTotals(seasons, len(gameLog.Games))
+	subject.PerGame = careerRates(subject.Career, subject.Career.Games)
+	subject.PerSeason = careerRates(subject.Career, subject.Career.Seasons)
+	// A carreira começa na primeira temporada com jogos; anos só com prêmios antes dela ficam de fora
+	firstYear := 0
+	for _, season := range seasons {
+		if season.Games > 0 {
+			firstYear = season.Year
+			break
+		}
+	}
+	for _, season := range seasons {
+		if firstYear == 0 || season.Year < firstYear {
+			continue
+		}
+		subject.Seasons = append(subject.Seasons, ComparisonSeason{SeasonNumber: season.Year - firstYear + 1, PlayerYearlyStats: season})
+	}
+
+	population, ok := positionTotals[player.Position]
+	if !ok {
+		population, err = getPositionCareerTotals(player.Position)
+		if err != nil {
+			return subject, err
+		}
+		positionTotals[player.Position] = population
+	}
+	subject.Percentiles = percentileRanks(subject.Career, population)
+	return subject, nil
+}
+
+func compareHistoricalRecord(recordID int) (ComparisonSubject, error) {
+	subject := ComparisonSubject{Key: fmt.Sprintf("record:%d", recordID), RecordID: recordID}
+
+	record, err := GetHistoricalRecord(recordID)
+	if err != nil {
+		return subject, err
+	}
+	subject.Name, subject.School = record.PlayerName, record.School
+
+	subject.Career = PlayerCareerTotals{
+		Seasons:        record.YearEnd - record.YearStart + 1,
+		Completions:    intValue(record.Completions),
+		PassingYards:   intValue(record.PassingYards),
+		PassingTDs:     intValue(record.Touchdowns),
+		RushingYards:   intValue(record.RushYards),
+		RushingTDs:     intValue(record.RushTDs),
+		ReceivingYards: intValue(record.ReceivingYards),
+		ReceivingTDs:   intValue(record.ReceivingTDs),
+	}
+	subject.PerSeason = careerRates(subject.Career, subject.Career.Seasons)
+	return subject, nil
+}
+
+// ComparePlayers compara lado a lado jogadores (atuais ou que já saíram) e recordes históricos.
+// As temporadas são alinhadas pelo número da temporada na carreira ("season") ou pelo ano ("year")
+func ComparePlayers(playerIDs []int, recordIDs []int, alignment string) (PlayerComparison, error) {
+	if alignment != "year" {
+		alignment = "season"
+	}
+	comparison := PlayerComparison{Alignment: alignment}
+	if len(playerIDs)+len(recordIDs) < 2 {
+		return comparison, ErrComparisonTooFew
+	}
+
+	positionTotals := make(map[string][]PlayerCareerTotals)
+	for _, playerID := range playerIDs {
+		subject, err := comparePlayer(playerID, positionTotals)
+		if err != nil {
+			return comparison, fmt.Errorf("jogador %d: %w", playerID, err)
+		}
+		comparison.Subjects = append(comparison.Subjects, subject)
+	}
+	for _, recordID := range recordIDs {
+		subject, err := compareHistoricalRecord(recordID)
+		if err != nil {
+			return comparison, fmt.Errorf("recorde %d: %w", recordID, err)
+		}
+		comparison.Subjects = append(comparison.Subjects, subject)
+	}
+
+	aligned := make(map[int][]*ComparisonSeason)
+	for i := range comparison.Subjects {
+		for j := range comparison.Subjects[i].Seasons {
+			season := &comparison.Subjects[i].Seasons[j]
+			key := season.SeasonNumber
+			if alignment == "year" {
+				key = season.Year
+			}
+			if aligned[key] == nil {
+				aligned[key] = make([]*ComparisonSeason, len(comparison.Subjects))
+			}
+			aligned[key][i] = season
+		}
+	}
+	for key, entries := range aligned {
+		comparison.Seasons = append(comparison.Seasons, AlignedSeason{Key: key, Entries: entries})
+	}
+	sort.Slice(comparison.Seasons, func(i, j int) bool { return comparison.Seasons[i].Key < comparison.Seasons[j].Key })
+	return comparison, nil
+}
@@ -0,0 +1,250 @@
+package services
+
+import (
+	"database/sql"
+	"dynastyTracker/database"
+	"dynastyTracker/models"
+	"errors"
+	"fmt"
+)
+
+// Motivos de saída do elenco
+const (
+	DepartureGraduated   = "graduated"
+	DepartureDeclared    = "declared"
+	DepartureTransferred = "transferred"
+	DepartureDismissed   = "dismissed"
+	DepartureMedical     = "medical"
+)
+
+var departureReasons = map[string]bool{
+	DepartureGraduated:   true,
+	DepartureDeclared:    true,
+	DepartureTransferred: true,
+	DepartureDismissed:   true,
+	DepartureMedical:     true,
+}
+
+const departureColumns = `d.departure_id, d.player_id, p.person_id, p.name, p.position, p.team_id, d.year, d.reason,
+        COALESCE(d.destination, ''), COALESCE(d.notes, '')`
+
+func scanDeparture(row rowScanner) (models.Departure, error) {
+	var departure models.Departure
+	err := row.Scan(&departure.DepartureID, &departure.PlayerID, &departure.PersonID, &departure.PlayerName, &departure.Position,
+		&departure.TeamID, &departure.Year, &departure.Reason, &departure.Destination, &departure.Notes)
+	return departure, err
+}
+
+// recordDeparture grava a saída e tira o jogador do elenco ativo, preservando suas estatísticas
+func recordDeparture(exec sqlExecer, departure models.Departure) error {
+	_, err := exec.Exec(`
+        INSERT INTO departures (player_id, year, reason, destination, notes)
+        VALUES (?, ?, ?, NULLIF(?, ''), NULLIF(?, ''))
+    `, departure.PlayerID, departure.Year, departure.Reason, departure.Destination, departure.Notes)
+	if err != nil {
+		fmt.Printf("Erro ao registrar saída do jogador: %v\n", err)
+		return err
+	}
+
+	_, err = exec.Exec("UPDATE players SET active = 0 WHERE player_id = ?", departure.PlayerID)
+	if err != nil {
+		fmt.Printf("Erro ao remover jogador do elenco: %v\n", err)
+	}
+	return err
+}
+
+// AddDeparture registra a saída de um jogador do elenco
+func AddDeparture(departure models.Departure) error {
+	if !departureReasons[departure.Reason] {
+		return fmt.Errorf("motivo de saída inválido: %q", departure.Reason)
+	}
+	if departure.Reason == DepartureTransferred && departure.Destination == "" {
+		return fmt.Errorf("transferências precisam da escola de destino")
+	}
+
+	var active bool
+	err := database.DB.QueryRow("SELECT active FROM players WHERE player_id = ?", departure.PlayerID).Scan(&active)
+	if err != nil {
+		return err
+	}
+	if !active {
+		return fmt.Errorf("o jogador %d já não faz parte do elenco", departure.PlayerID)
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := recordDeparture(tx, departure); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetDeparture obtém uma saída pelo ID
+func GetDeparture(id int) (models.Departure, error) {
+	row := database.DB.QueryRow(`SELECT `+departureColumns+`
+        FROM departures d
+        JOIN players p ON p.player_id = d.player_id
+        WHERE d.departure_id = ?`, id)
+	return scanDeparture(row)
+}
+
+// UpdateDeparture corrige os dados de uma saída (motivo, destino, observações)
+func UpdateDeparture(departure models.Departure) error {
+	if !departureReasons[departure.Reason] {
+		return fmt.Errorf("motivo de saída inválido: %q", departure.Reason)
+	}
+	_, err := database.DB.Exec(`
+        UPDATE departures SET year=?, reason=?, destination=NULLIF(?, ''), notes=NULLIF(?, '')
+        WHERE departure_id=?
+    `, departure.Year, departure.Reason, departure.Destination, departure.Notes, departure.DepartureID)
+	return err
+}
+
+// ErrDepartureExhausted impede desfazer a saída de quem esgotou a elegibilidade: o jogador não
+// pode voltar ao elenco ativo sem corrigir antes o ano acadêmico
+var ErrDepartureExhausted = errors.New("o jogador esgotou a elegibilidade e não pode voltar ao elenco")
+
+// DeleteDeparture desfaz uma saída registrada por engano, devolvendo o jogador ao elenco dentro
+// das regras de elenco. A declaração ao draft de uma saída "declared" é apagada e a transferência
+// concluída de uma saída "transferred" volta a ficar aberta no portal
+func DeleteDeparture(id int) error {
+	departure, err := GetDeparture(id)
+	if err != nil {
+		return err
+	}
+
+	return withRosterRules([]int{departure.TeamID}, func(tx *sql.Tx) error {
+		var exhausted bool
+		err := tx.QueryRow("SELECT eligibility_exhausted FROM players WHERE player_id = ? FOR UPDATE", departure.PlayerID).
+			Scan(&exhausted)
+		if err != nil {
+			return err
+		}
+		if exhausted {
+			return ErrDepartureExhausted
+		}
+
+		result, err := tx.Exec("DELETE FROM departures WHERE departure_id = ?", id)
+		if err != nil {
+			return err
+		}
+		// Outra requisição desfez a mesma saída primeiro
+		if affected, _ := result.RowsAffected(); affected == 0 {
+			return sql.ErrNoRows
+		}
+		if _, err := tx.Exec("UPDATE players SET active = 1 WHERE player_id = ?", departure.PlayerID); err != nil {
+			return err
+		}
+
+		switch departure.Reason {
+		case DepartureDeclared:
+			_, err = tx.Exec("DELETE FROM draft_declarations WHERE player_id = ? AND year = ?", departure.PlayerID, departure.Year)
+		case DepartureTransferred:
+			_, err = tx.Exec(`
+                UPDATE portal_transfers SET status = ?, commit_week = NULL, destination = NULL
+                WHERE player_id = ? AND direction = ? AND status = ? AND year = ?
+            `, PortalInPortal, departure.PlayerID, TransferOutgoing, PortalCommitted, departure.Year)
+		}
+		if err != nil {
+			fmt.Printf("Erro ao desfazer registros da saída: %v\n", err)
+		}
+		return err
+	})
+}
+
+// GetDeparturesWithFilters lista as saídas por time, temporada e motivo
+func GetDeparturesWithFilters(teamID int, year int, reason string) ([]models.Departure, error) {
+	query := `SELECT ` + departureColumns + `
+        FROM departures d
+        JOIN players p ON p.player_id = d.player_id
+        WHERE 1=1`
+	var args []interface{}
+	if teamID > 0 {
+		query += " AND p.team_id = ?"
+		args = append(args, teamID)
+	}
+	if year > 0 {
+		query += " AND d.year = ?"
+		args = append(args, year)
+	}
+	if reason != "" {
+		query += " AND d.reason = ?"
+		args = append(args, reason)
+	}
+	query += " ORDER BY d.year, p.position, p.name"
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		fmt.Printf("Erro ao executar consulta: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var departures []models.Departure
+	for rows.Next() {
+		departure, err := scanDeparture(rows)
+		if err != nil {
+			fmt.Printf("Erro ao escanear resultados: %v\n", err)
+			return nil, err
+		}
+		departures = append(departures, departure)
+	}
+	return departures, nil
+}
+
+type AttritionReport struct {
+	Year        int    `json:"year"`
+	Position    string `json:"position"`
+	Graduated   int    `json:"graduated"`
+	Declared    int    `json:"declared"`
+	Transferred int    `json:"transferred"`
+	Dismissed   int    `json:"dismissed"`
+	Medical     int    `json:"medical"`
+	Total       int    `json:"total"`
+}
+
+// GetAttritionReport conta as saídas do elenco por temporada, posição e motivo
+func GetAttritionReport(teamID int) ([]AttritionReport, error) {
+	query := `
+        SELECT d.year, p.position,
+            SUM(CASE WHEN d.reason = 'graduated' THEN 1 ELSE 0 END) AS graduated,
+            SUM(CASE WHEN d.reason = 'declared' THEN 1 ELSE 0 END) AS declared,
+            SUM(CASE WHEN d.reason = 'transferred' THEN 1 ELSE 0 END) AS transferred,
+            SUM(CASE WHEN d.reason = 'dismissed' THEN 1 ELSE 0 END) AS dismissed,
+            SUM(CASE WHEN d.reason = 'medical' THEN 1 ELSE 0 END) AS medical,
+            COUNT(*) AS total
+        FROM departures d
+        JOIN players p ON p.player_id = d.player_id`
+	var args []interface{}
+	if teamID > 0 {
+		query += " WHERE p.team_id = ?"
+		args = append(args, teamID)
+	}
+	query += `
+        GROUP BY d.year, p.position
+        ORDER BY d.year, p.position`
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		fmt.Printf("Erro ao executar consulta: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reports []AttritionReport
+	for rows.Next() {
+		var report AttritionReport
+		err := rows.Scan(&report.Year, &report.Position, &report.Graduated, &report.Declared, &report.Transferred,
+			&report.Dismissed, &report.Medical, &report.Total)
+		if err != nil {
+			fmt.Printf("Erro ao escanear resultados: %v\n", err)
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
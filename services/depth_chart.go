@@ -0,0 +1,400 @@
+package services
+
+import (
+	"database/sql"
+	"dynastyTracker/database"
+	"dynastyTracker/models"
+	"fmt"
+	"sort"
+)
+
+// DepthChartSlot descreve uma vaga da escalação e as posições que podem ocupá-la
+type DepthChartSlot struct {
+	Slot       string   `json:"slot"`
+	Positions  []string `json:"positions"`   // Vazio = qualquer posição
+	Starters   int      `json:"starters"`    // Quantos jogadores da vaga são titulares
+	SubPackage bool     `json:"sub_package"` // Pacotes especiais não contam como jogo iniciado
+}
+
+// depthChartSlots define a escalação base, os pacotes especiais e os retornadores
+var depthChartSlots = []DepthChartSlot{
+	{Slot: "QB", Positions: []string{"QB"}, Starters: 1},
+	{Slot: "HB", Positions: []string{"HB"}, Starters: 1},
+	{Slot: "FB", Positions: []string{"FB", "HB", "TE"}, Starters: 1},
+	{Slot: "WR", Positions: []string{"WR"}, Starters: 3},
+	{Slot: "TE", Positions: []string{"TE"}, Starters: 1},
+	{Slot: "LT", Positions: []string{"LT", "LG", "C", "RG", "RT"}, Starters: 1},
+	{Slot: "LG", Positions: []string{"LT", "LG", "C", "RG", "RT"}, Starters: 1},
+	{Slot: "C", Positions: []string{"LT", "LG", "C", "RG", "RT"}, Starters: 1},
+	{Slot: "RG", Positions: []string{"LT", "LG", "C", "RG", "RT"}, Starters: 1},
+	{Slot: "RT", Positions: []string{"LT", "LG", "C", "RG", "RT"}, Starters: 1},
+	{Slot: "LEDG", Positions: []string{"LEDG", "REDG", "DT"}, Starters: 1},
+	{Slot: "REDG", Positions: []string{"LEDG", "REDG", "DT"}, Starters: 1},
+	{Slot: "DT", Positions: []string{"DT", "LEDG", "REDG"}, Starters: 2},
+	{Slot: "SAM", Positions: []string{"SAM", "MIKE", "WILL"}, Starters: 1},
+	{Slot: "MIKE", Positions: []string{"SAM", "MIKE", "WILL"}, Starters: 1},
+	{Slot: "WILL", Positions: []string{"SAM", "MIKE", "WILL"}, Starters: 1},
+	{Slot: "CB", Positions: []string{"CB"}, Starters: 2},
+	{Slot: "FS", Positions: []string{"FS", "SS"}, Starters: 1},
+	{Slot: "SS", Positions: []string{"FS", "SS"}, Starters: 1},
+	{Slot: "K", Positions: []string{"K", "P"}, Starters: 1},
+	{Slot: "P", Positions: []string{"P", "K"}, Starters: 1},
+	{Slot: "NCB", Positions: []string{"CB", "FS", "SS"}, Starters: 1, SubPackage: true},
+	{Slot: "DCB", Positions: []string{"CB", "FS", "SS"}, Starters: 1, SubPackage: true},
+	{Slot: "3DRB", Positions: []string{"HB", "FB", "WR"}, Starters: 1, SubPackage: true},
+	{Slot: "PWHB", Positions: []string{"HB", "FB"}, Starters: 1, SubPackage: true},
+	{Slot: "KR", Starters: 2, SubPackage: true},
+	{Slot: "PR", Starters: 1, SubPackage: true},
+	{Slot: "LS", Positions: []string{"C", "LG", "RG", "TE"}, Starters: 1, SubPackage: true},
+}
+
+// GetDepthChartSlots retorna as vagas disponíveis na escalação
+func GetDepthChartSlots() []DepthChartSlot {
+	return depthChartSlots
+}
+
+func findDepthChartSlot(slot string) (DepthChartSlot, bool) {
+	for _, definition := range depthChartSlots {
+		if definition.Slot == slot {
+			return definition, true
+		}
+	}
+	return DepthChartSlot{}, false
+}
+
+func slotAllowsPosition(definition DepthChartSlot, position string) bool {
+	if len(definition.Positions) == 0 {
+		return true
+	}
+	for _, allowed := range definition.Positions {
+		if allowed == position {
+			return true
+		}
+	}
+	return false
+}
+
+// isStarter indica se a entrada é titular da escalação base
+func isStarter(entry models.DepthChartEntry) bool {
+	definition, ok := findDepthChartSlot(entry.Slot)
+	return ok && !definition.SubPackage && entry.Depth <= definition.Starters
+}
+
+type DepthChartValidation struct {
+	Errors   []string `json:"errors"`
+	Warnings []string `json:"warnings"` // Não impedem a gravação, ex: jogador lesionado escalado
+}
+
+// ValidateDepthChart confere a escalação contra o elenco do time e as posições de cada vaga
+func ValidateDepthChart(chart models.DepthChart) (DepthChartValidation, error) {
+	var validation DepthChartValidation
+
+	rows, err := database.DB.Query(`
+        SELECT player_id, position FROM players
+        WHERE team_id = ? AND active = 1 AND eligibility_exhausted = 0
+    `, chart.TeamID)
+	if err != nil {
+		fmt.Printf("Erro ao buscar elenco: %v\n", err)
+		return validation, err
+	}
+	defer rows.Close()
+
+	roster := make(map[int]string)
+	for rows.Next() {
+		var playerID int
+		var position string
+		if err := rows.Scan(&playerID, &position); err != nil {
+			return validation, err
+		}
+		roster[playerID] = position
+	}
+
+	unavailable, err := GetUnavailablePlayers(chart.TeamID, chart.Year, chart.Week)
+	if err != nil {
+		return validation, err
+	}
+	injured := make(map[int]UnavailablePlayer)
+	for _, player := range unavailable {
+		injured[player.PlayerID] = player
+	}
+
+	depthsBySlot := make(map[string][]int)
+	playersBySlot := make(map[string]map[int]bool)
+	for _, entry := range chart.Entries {
+		definition, ok := findDepthChartSlot(entry.Slot)
+		if !ok {
+			validation.Errors = append(validation.Errors, fmt.Sprintf("vaga desconhecida: %s", entry.Slot))
+			continue
+		}
+
+		position, onRoster := roster[entry.PlayerID]
+		if !onRoster {
+			validation.Errors = append(validation.Errors,
+				fmt.Sprintf("jogador %d não faz parte do elenco do time (%s)", entry.PlayerID, entry.Slot))
+			continue
+		}
+		if !slotAllowsPosition(definition, position) {
+			validation.Errors = append(validation.Errors,
+				fmt.Sprintf("jogador %d (%s) não pode ocupar a vaga %s", entry.PlayerID, position, entry.Slot))
+		}
+		if injury, ok := injured[entry.PlayerID]; ok {
+			validation.Warnings = append(validation.Warnings,
+				fmt.Sprintf("jogador %d está lesionado (%s - %s) e foi escalado na vaga %s",
+					entry.PlayerID, injury.InjuryType, injury.BodyPart, entry.Slot))
+		}
+
+		if playersBySlot[entry.Slot] == nil {
+			playersBySlot[entry.Slot] = make(map[int]bool)
+		}
+		if playersBySlot[entry.Slot][entry.PlayerID] {
+			validation.Errors = append(validation.Errors,
+				fmt.Sprintf("jogador %d aparece mais de uma vez na vaga %s", entry.PlayerID, entry.Slot))
+		}
+		playersBySlot[entry.Slot][entry.PlayerID] = true
+		depthsBySlot[entry.Slot] = append(depthsBySlot[entry.Slot], entry.Depth)
+	}
+
+	// A profundidade de cada vaga precisa ser sequencial a partir de 1
+	for slot, depths := range depthsBySlot {
+		sort.Ints(depths)
+		for i, depth := range depths {
+			if depth != i+1 {
+				validation.Errors = append(validation.Errors,
+					fmt.Sprintf("profundidade inválida na vaga %s: esperado %d, recebido %d", slot, i+1, depth))
+				break
+			}
+		}
+	}
+
+	return validation, nil
+}
+
+// SaveDepthChart valida e grava a escalação da semana, substituindo a anterior da mesma semana
+func SaveDepthChart(chart models.DepthChart) (DepthChartValidation, error) {
+	validation, err := ValidateDepthChart(chart)
+	if err != nil || len(validation.Errors) > 0 {
+		return validation, err
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return validation, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("DELETE FROM depth_charts WHERE team_id = ? AND year = ? AND week = ?", chart.TeamID, chart.Year, chart.Week)
+	if err != nil {
+		fmt.Printf("Erro ao limpar escalação: %v\n", err)
+		return validation, err
+	}
+
+	for _, entry := range chart.Entries {
+		_, err = tx.Exec(`
+            INSERT INTO depth_charts (team_id, year, week, slot, depth, player_id)
+            VALUES (?, ?, ?, ?, ?, ?)
+        `, chart.TeamID, chart.Year, chart.Week, entry.Slot, entry.Depth, entry.PlayerID)
+		if err != nil {
+			fmt.Printf("Erro ao gravar escalação: %v\n", err)
+			return validation, err
+		}
+	}
+
+	// Os jogos iniciados são recalculados na mesma transação, para não ficarem defasados
+	if err := recalculateGamesStarted(tx, chart.TeamID); err != nil {
+		return validation, err
+	}
+	return validation, tx.Commit()
+}
+
+// getDepthChartEntries busca as entradas de escalação do time. year = 0 e week < 0
+// retornam todas as temporadas e semanas (a semana 0 existe no calendário)
+func getDepthChartEntries(q sqlQueryer, teamID int, year int, week int) ([]models.DepthChartEntry, error) {
+	query := `
+        SELECT d.team_id, d.year, d.week, d.slot, d.depth, d.player_id, COALESCE(p.name, '')
+        FROM depth_charts d
+        LEFT JOIN players p ON p.player_id = d.player_id
+        WHERE d.team_id = ?
+    `
+	args := []interface{}{teamID}
+	if year > 0 {
+		query += " AND d.year = ?"
+		args = append(args, year)
+	}
+	if week >= 0 {
+		query += " AND d.week = ?"
+		args = append(args, week)
+	}
+	query += " ORDER BY d.year, d.week, d.slot, d.depth"
+
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		fmt.Printf("Erro ao executar consulta: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []models.DepthChartEntry
+	for rows.Next() {
+		var entry models.DepthChartEntry
+		err := rows.Scan(&entry.TeamID, &entry.Year, &entry.Week, &entry.Slot, &entry.Depth, &entry.PlayerID, &entry.PlayerName)
+		if err != nil {
+			fmt.Printf("Erro ao escanear resultados: %v\n", err)
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// GetDepthChart retorna a escalação em vigor na semana: a última gravada até aquela semana
+func GetDepthChart(teamID int, year int, week int) (models.DepthChart, error) {
+	chart := models.DepthChart{TeamID: teamID, Year: year}
+
+	var effectiveWeek sql.NullInt64
+	err := database.DB.QueryRow(`
+        SELECT MAX(week) FROM depth_charts
+        WHERE team_id = ? AND year = ? AND week <= ?
+    `, teamID, year, week).Scan(&effectiveWeek)
+	if err != nil {
+		return chart, err
+	}
+	if !effectiveWeek.Valid {
+		return chart, sql.ErrNoRows
+	}
+
+	chart.Week = int(effectiveWeek.Int64)
+	chart.Entries, err = getDepthChartEntries(database.DB, teamID, year, chart.Week)
+	return chart, err
+}
+
+// GetDepthChartHistory retorna todas as escalações gravadas do time na temporada, por semana
+func GetDepthChartHistory(teamID int, year int) ([]models.DepthChart, error) {
+	entries, err := getDepthChartEntries(database.DB, teamID, year, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	var history []models.DepthChart
+	for _, entry := range entries {
+		if len(history) == 0 || history[len(history)-1].Week != entry.Week {
+			history = append(history, models.DepthChart{TeamID: teamID, Year: year, Week: entry.Week})
+		}
+		current := &history[len(history)-1]
+		current.Entries = append(current.Entries, entry)
+	}
+	return history, nil
+}
+
+// GetGameStarters retorna os titulares da escalação base em vigor para o jogo
+func GetGameStarters(scheduleID int) ([]models.DepthChartEntry, error) {
+	var teamID, year, week int
+	err := database.DB.QueryRow("SELECT team_id, year, week FROM schedule WHERE id = ?", scheduleID).Scan(&teamID, &year, &week)
+	if err != nil {
+		return nil, err
+	}
+
+	chart, err := GetDepthChart(teamID, year, week)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var starters []models.DepthChartEntry
+	for _, entry := range chart.Entries {
+		if isStarter(entry) {
+			starters = append(starters, entry)
+		}
+	}
+	return starters, nil
+}
+
+// RecalculateGamesStarted recalcula players.games_started a partir das escalações
+// em vigor em cada jogo já disputado pelo time
+func RecalculateGamesStarted(teamID int) error {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := recalculateGamesStarted(tx, teamID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func recalculateGamesStarted(tx sqlRunner, teamID int) error {
+	rows, err := tx.Query(`
+        SELECT year, week FROM schedule
+        WHERE team_id = ? AND result IS NOT NULL AND result <> ''
+    `, teamID)
+	if err != nil {
+		fmt.Printf("Erro ao buscar jogos do time: %v\n", err)
+		return err
+	}
+	type gameWeek struct{ year, week int }
+	var games []gameWeek
+	for rows.Next() {
+		var game gameWeek
+		if err := rows.Scan(&game.year, &game.week); err != nil {
+			rows.Close()
+			return err
+		}
+		games = append(games, game)
+	}
+	rows.Close()
+
+	entries, err := getDepthChartEntries(tx, teamID, 0, -1)
+	if err != nil {
+		return err
+	}
+
+	// Agrupar as escalações por temporada e semana em que foram gravadas
+	chartsByYear := make(map[int]map[int][]models.DepthChartEntry)
+	for _, entry := range entries {
+		if chartsByYear[entry.Year] == nil {
+			chartsByYear[entry.Year] = make(map[int][]models.DepthChartEntry)
+		}
+		chartsByYear[entry.Year][entry.Week] = append(chartsByYear[entry.Year][entry.Week], entry)
+	}
+
+	starts := make(map[int]int)
+	for _, game := range games {
+		effectiveWeek := -1
+		for week := range chartsByYear[game.year] {
+			if week <= game.week && week > effectiveWeek {
+				effectiveWeek = week
+			}
+		}
+		if effectiveWeek < 0 {
+			continue
+		}
+
+		// Um jogador em duas vagas titulares conta apenas um jogo iniciado
+		started := make(map[int]bool)
+		for _, entry := range chartsByYear[game.year][effectiveWeek] {
+			if isStarter(entry) {
+				started[entry.PlayerID] = true
+			}
+		}
+		for playerID := range started {
+			starts[playerID]++
+		}
+	}
+
+	_, err = tx.Exec("UPDATE players SET games_started = 0 WHERE team_id = ?", teamID)
+	if err != nil {
+		return err
+	}
+	for playerID, count := range starts {
+		_, err = tx.Exec("UPDATE players SET games_started = ? WHERE player_id = ?", count, playerID)
+		if err != nil {
+			fmt.Printf("Erro ao atualizar jogos iniciados: %v\n", err)
+			return err
+		}
+	}
+
+	return nil
+}
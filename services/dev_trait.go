@@ -0,0 +1,227 @@
+package services
+
+import (
+	"database/sql"
+	"dynastyTracker/database"
+	"dynastyTracker/models"
+	"fmt"
+	"strings"
+)
+
+// devTraitTiers ordena os dev traits do menor para o maior
+var devTraitTiers = map[string]int{
+	"Normal": 0,
+	"Impact": 1,
+	"Star":   2,
+	"Elite":  3,
+}
+
+// NormalizeDevTrait padroniza o dev trait (ex: "star" -> "Star")
+func NormalizeDevTrait(devTrait string) (string, error) {
+	for trait := range devTraitTiers {
+		if strings.EqualFold(trait, strings.TrimSpace(devTrait)) {
+			return trait, nil
+		}
+	}
+	return "", fmt.Errorf("dev trait inválido: %q", devTrait)
+}
+
+// addDevTraitEvent grava um evento na linha do tempo do dev trait
+func addDevTraitEvent(exec sqlExecer, event models.DevTraitEvent) error {
+	_, err := exec.Exec(`
+        INSERT INTO player_dev_trait_history (player_id, year, event, previous_trait, dev_trait)
+        VALUES (?, ?, ?, ?, ?)
+    `, event.PlayerID, event.Year, event.Event, event.PreviousTrait, event.DevTrait)
+	if err != nil {
+		fmt.Printf("Erro ao gravar histórico de dev trait: %v\n", err)
+	}
+	return err
+}
+
+// RevealDevTrait registra a revelação do dev trait oculto do jogador na temporada.
+// O trait revelado pode ser diferente do informado no recrutamento.
+func RevealDevTrait(playerID int, year int, devTrait string) error {
+	devTrait, err := NormalizeDevTrait(devTrait)
+	if err != nil {
+		return err
+	}
+
+	var previous string
+	var revealedYear sql.NullInt64
+	err = database.DB.QueryRow("SELECT COALESCE(dev_trait, ''), dev_trait_revealed_year FROM players WHERE player_id = ?", playerID).
+		Scan(&previous, &revealedYear)
+	if err != nil {
+		return err
+	}
+	if revealedYear.Valid {
+		return fmt.Errorf("o dev trait do jogador já foi revelado em %d", revealedYear.Int64)
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("UPDATE players SET dev_trait = ?, dev_trait_revealed_year = ? WHERE player_id = ?", devTrait, year, playerID)
+	if err != nil {
+		return err
+	}
+	err = addDevTraitEvent(tx, models.DevTraitEvent{PlayerID: playerID, Year: year, Event: "revealed", PreviousTrait: previous, DevTrait: devTrait})
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// UpgradeDevTrait registra a evolução do dev trait para um nível superior
+func UpgradeDevTrait(playerID int, year int, devTrait string) error {
+	devTrait, err := NormalizeDevTrait(devTrait)
+	if err != nil {
+		return err
+	}
+
+	var previous string
+	err = database.DB.QueryRow("SELECT COALESCE(dev_trait, '') FROM players WHERE player_id = ?", playerID).Scan(&previous)
+	if err != nil {
+		return err
+	}
+	if previousTier, ok := devTraitTiers[previous]; ok && devTraitTiers[devTrait] <= previousTier {
+		return fmt.Errorf("o novo dev trait (%s) precisa ser superior ao atual (%s)", devTrait, previous)
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Um upgrade também revela o trait, caso ainda estivesse oculto
+	_, err = tx.Exec(`
+        UPDATE players SET dev_trait = ?, dev_trait_revealed_year = COALESCE(dev_trait_revealed_year, ?)
+        WHERE player_id = ?
+    `, devTrait, year, playerID)
+	if err != nil {
+		return err
+	}
+	err = addDevTraitEvent(tx, models.DevTraitEvent{PlayerID: playerID, Year: year, Event: "upgraded", PreviousTrait: previous, DevTrait: devTrait})
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetDevTraitHistory retorna a linha do tempo do dev trait do jogador
+func GetDevTraitHistory(playerID int) ([]models.DevTraitEvent, error) {
+	rows, err := database.DB.Query(`
+        SELECT player_id, year, event, COALESCE(previous_trait, ''), dev_trait
+        FROM player_dev_trait_history
+        WHERE player_id = ?
+        ORDER BY year, history_id
+    `, playerID)
+	if err != nil {
+		fmt.Printf("Erro ao executar consulta: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []models.DevTraitEvent
+	for rows.Next() {
+		var event models.DevTraitEvent
+		if err := rows.Scan(&event.PlayerID, &event.Year, &event.Event, &event.PreviousTrait, &event.DevTrait); err != nil {
+			fmt.Printf("Erro ao escanear resultados: %v\n", err)
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+type DevTraitImpactReport struct {
+	DevTrait            string  `json:"dev_trait"`
+	Players             int     `json:"players"`
+	Revealed            int     `json:"revealed"`
+	Upgraded            int     `json:"upgraded"`
+	AvgInitialOverall   float64 `json:"avg_initial_overall"`
+	AvgCurrentOverall   float64 `json:"avg_current_overall"`
+	AvgOverallGrowth    float64 `json:"avg_overall_growth"`
+	AvgGrowthPerSeason  float64 `json:"avg_growth_per_season"`
+	GamesPlayed         int     `json:"games_played"`
+	YardsPerGame        float64 `json:"yards_per_game"`      // Jardas de scrimmage (corrida e recepção)
+	TouchdownsPerGame   float64 `json:"touchdowns_per_game"` // TDs de scrimmage
+	PassingYardsPerGame float64 `json:"passing_yards_per_game"`
+	PassingTDsPerGame   float64 `json:"passing_tds_per_game"`
+	TotalScrimmageYards int     `json:"total_scrimmage_yards"`
+	TotalTouchdowns     int     `json:"total_touchdowns"` // TDs de scrimmage
+	TotalPassingYards   int     `json:"total_passing_yards"`
+	TotalPassingTDs     int     `json:"total_passing_tds"`
+}
+
+// GetDevTraitImpactReport correlaciona o dev trait com o crescimento de overall e a produção em campo.
+// O overall inicial é o primeiro registro em player_ratings (ou o atual, se não houver histórico).
+func GetDevTraitImpactReport(position string) ([]DevTraitImpactReport, error) {
+	query := `
+        SELECT COALESCE(NULLIF(p.dev_trait, ''), 'Unknown') AS trait_group,
+            COUNT(*) AS players,
+            SUM(CASE WHEN p.dev_trait_revealed_year IS NOT NULL THEN 1 ELSE 0 END) AS revealed,
+            SUM(CASE WHEN up.player_id IS NOT NULL THEN 1 ELSE 0 END) AS upgraded,
+            AVG(COALESCE(first_rating.overall, p.overall)) AS avg_initial_overall,
+            AVG(p.overall) AS avg_current_overall,
+            AVG(p.overall - COALESCE(first_rating.overall, p.overall)) AS avg_overall_growth,
+            AVG((p.overall - COALESCE(first_rating.overall, p.overall)) / GREATEST(COALESCE(ratings.seasons, 1) - 1, 1)) AS avg_growth_per_season,
+            COALESCE(SUM(production.games), 0) AS games_played,
+            COALESCE(SUM(production.scrimmage_yards), 0) AS total_scrimmage_yards,
+            COALESCE(SUM(production.scrimmage_tds), 0) AS total_touchdowns,
+            COALESCE(SUM(production.passing_yards), 0) AS total_passing_yards,
+            COALESCE(SUM(production.passing_tds), 0) AS total_passing_tds
+        FROM players p
+        LEFT JOIN (
+            SELECT r.player_id, r.overall
+            FROM player_ratings r
+            JOIN (SELECT player_id, MIN(year) AS first_year FROM player_ratings GROUP BY player_id) f
+              ON f.player_id = r.player_id AND f.first_year = r.year
+        ) first_rating ON first_rating.player_id = p.player_id
+        LEFT JOIN (
+            SELECT player_id, COUNT(*) AS seasons FROM player_ratings GROUP BY player_id
+        ) ratings ON ratings.player_id = p.player_id
+        LEFT JOIN (
+            SELECT DISTINCT player_id FROM player_dev_trait_history WHERE event = 'upgraded'
+        ) up ON up.player_id = p.player_id
+        LEFT JOIN ` + productionByPlayer + ` production ON production.player_id = p.player_id
+        WHERE 1=1`
+	var args []interface{}
+	if position != "" {
+		query += " AND p.position = ?"
+		args = append(args, position)
+	}
+	query += `
+        GROUP BY trait_group
+        ORDER BY FIELD(trait_group, 'Elite', 'Star', 'Impact', 'Normal', 'Unknown')`
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		fmt.Printf("Erro ao executar consulta: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reports []DevTraitImpactReport
+	for rows.Next() {
+		var report DevTraitImpactReport
+		err := rows.Scan(&report.DevTrait, &report.Players, &report.Revealed, &report.Upgraded,
+			&report.AvgInitialOverall, &report.AvgCurrentOverall, &report.AvgOverallGrowth, &report.AvgGrowthPerSeason,
+			&report.GamesPlayed, &report.TotalScrimmageYards, &report.TotalTouchdowns, &report.TotalPassingYards, &report.TotalPassingTDs)
+		if err != nil {
+			fmt.Printf("Erro ao escanear resultados: %v\n", err)
+			return nil, err
+		}
+		if report.GamesPlayed > 0 {
+			report.YardsPerGame = float64(report.TotalScrimmageYards) / float64(report.GamesPlayed)
+			report.TouchdownsPerGame = float64(report.TotalTouchdowns) / float64(report.GamesPlayed)
+			report.PassingYardsPerGame = float64(report.TotalPassingYards) / float64(report.GamesPlayed)
+			report.PassingTDsPerGame = float64(report.TotalPassingTDs) / float64(report.GamesPlayed)
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"database/sql"
+	"dynastyTracker/database"
+	"dynastyTracker/models"
+	"errors"
+	"fmt"
+)
+
+// ErrAlreadyDeclared impede uma segunda declaração do mesmo jogador. Quem volta atrás tem a
+// declaração excluída (DeleteDraftEntry), então cada jogador tem no máximo uma
+var ErrAlreadyDeclared = errors.New("o jogador já tem uma declaração para o draft")
+
+const draftColumns = `d.draft_id, d.player_id, p.name, p.position, p.team_id, d.year, d.early_declaration,
+        d.round, d.pick, d.overall_pick, COALESCE(d.nfl_team, ''), p.recruit_id, r.stars`
+
+const draftJoins = `
+        FROM draft_declarations d
+        JOIN players p ON p.player_id = d.player_id
+        LEFT JOIN recruits r ON r.recruit_id = p.recruit_id`
+
+func scanDraftEntry(row rowScanner) (models.DraftEntry, error) {
+	var entry models.DraftEntry
+	err := row.Scan(&entry.DraftID, &entry.PlayerID, &entry.PlayerName, &entry.Position, &entry.TeamID, &entry.Year,
+		&entry.EarlyDeclaration, &entry.Round, &entry.Pick, &entry.OverallPick, &entry.NFLTeam, &entry.RecruitID, &entry.Stars)
+	return entry, err
+}
+
+// AddDraftDeclaration registra a declaração do jogador para o draft. A declaração é
+// antecipada quando o jogador ainda tinha elegibilidade (não é SR/RS SR)
+func AddDraftDeclaration(entry models.DraftEntry) error {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Travar o jogador impede duas declarações simultâneas
+	var classYear models.ClassYear
+	var exhausted bool
+	err = tx.QueryRow("SELECT class_year, eligibility_exhausted FROM players WHERE player_id = ? FOR UPDATE", entry.PlayerID).
+		Scan(&classYear, &exhausted)
+	if err != nil {
+		return err
+	}
+	var declared int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM draft_declarations WHERE player_id = ?", entry.PlayerID).Scan(&declared); err != nil {
+		return err
+	}
+	if declared > 0 {
+		return ErrAlreadyDeclared
+	}
+
+	early := !exhausted && classYear != models.ClassSenior && classYear != models.ClassRedshirtSenior
+
+	_, err = tx.Exec(`
+        INSERT INTO draft_declarations (player_id, year, early_declaration, round, pick, overall_pick, nfl_team)
+        VALUES (?, ?, ?, ?, ?, ?, NULLIF(?, ''))
+    `, entry.PlayerID, entry.Year, early, entry.Round, entry.Pick, entry.OverallPick, entry.NFLTeam)
+	if err != nil {
+		fmt.Printf("Erro ao registrar declaração para o draft: %v\n", err)
+		return err
+	}
+	return tx.Commit()
+}
+
+// UpdateDraftResult registra a rodada, a escolha e o time da NFL do jogador
+func UpdateDraftResult(entry models.DraftEntry) error {
+	result, err := database.DB.Exec(`
+        UPDATE draft_declarations SET year=?, round=?, pick=?, overall_pick=?, nfl_team=NULLIF(?, '')
+        WHERE draft_id=?
+    `, entry.Year, entry.Round, entry.Pick, entry.OverallPick, entry.NFLTeam, entry.DraftID)
+	if err != nil {
+		return err
+	}
+	// O MySQL não conta linhas sem mudança, então zero linhas só indica ausência se a declaração não existir
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		var exists int
+		err := database.DB.QueryRow("SELECT COUNT(*) FROM draft_declarations WHERE draft_id = ?", entry.DraftID).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if exists == 0 {
+			return sql.ErrNoRows
+		}
+	}
+	return nil
+}
+
+// GetDraftEntry obtém uma declaração para o draft pelo ID
+func GetDraftEntry(id int) (models.DraftEntry, error) {
+	row := database.DB.QueryRow("SELECT "+draftColumns+draftJoins+" WHERE d.draft_id = ?", id)
+	return scanDraftEntry(row)
+}
+
+// DeleteDraftEntry exclui uma declaração para o draft (ex: jogador voltou atrás)
+func DeleteDraftEntry(id int) error {
+	_, err := database.DB.Exec("DELETE FROM draft_declarations WHERE draft_id = ?", id)
+	return err
+}
+
+// GetDraftEntries lista as declarações e resultados do draft, filtrando por ano e time
+func GetDraftEntries(year int, teamID int) ([]models.DraftEntry, error) {
+	query := "SELECT " + draftColumns + draftJoins + " WHERE 1=1"
+	var args []interface{}
+	if year > 0 {
+		query += " AND d.year = ?"
+		args = append(args, year)
+	}
+	if teamID > 0 {
+		query += " AND p.team_id = ?"
+		args = append(args, teamID)
+	}
+	query += " ORDER BY d.year, d.overall_pick IS NULL, d.overall_pick, p.name"
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		fmt.Printf("Erro ao executar consulta: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []models.DraftEntry
+	for rows.Next() {
+		entry, err := scanDraftEntry(rows)
+		if err != nil {
+			fmt.Printf("Erro ao escanear resultados: %v\n", err)
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+type DraftGroupSummary struct {
+	Group             string `json:"group"` // Ano, posição ou estrelas, conforme o agrupamento
+	Declarations      int    `json:"declarations"`
+	EarlyDeclarations int    `json:"early_declarations"`
+	Picks             int    `json:"picks"`
+	FirstRounders     int    `json:"first_rounders"`
+	Undrafted         int    `json:"undrafted"`
+}
+
+type DraftReport struct {
+	BySeason   []DraftGroupSummary `json:"by_season"`
+	ByPosition []DraftGroupSummary `json:"by_position"`
+	ByStars    []DraftGroupSummary `json:"by_stars"`
+	Picks      []models.DraftEntry `json:"picks"`
+}
+
+// GetDraftReport resume as escolhas de draft do programa por temporada, posição e
+// estrelas do recrutamento de origem
+func GetDraftReport(teamID int) (DraftReport, error) {
+	var report DraftReport
+
+	entries, err := GetDraftEntries(0, teamID)
+	if err != nil {
+		return report, err
+	}
+
+	summarize := func(groups map[string]*DraftGroupSummary, order *[]string, key string, entry models.DraftEntry) {
+		summary, ok := groups[key]
+		if !ok {
+			summary = &DraftGroupSummary{Group: key}
+			groups[key] = summary
+			*order = append(*order, key)
+		}
+		summary.Declarations++
+		if entry.EarlyDeclaration {
+			summary.EarlyDeclarations++
+		}
+		switch {
+		case entry.Round == nil:
+			// Resultado ainda não registrado
+		case *entry.Round == 0:
+			summary.Undrafted++
+		default:
+			summary.Picks++
+			if *entry.Round == 1 {
+				summary.FirstRounders++
+			}
+		}
+	}
+
+	seasons, positions, stars := map[string]*DraftGroupSummary{}, map[string]*DraftGroupSummary{}, map[string]*DraftGroupSummary{}
+	var seasonOrder, positionOrder, starsOrder []string
+	for _, entry := range entries {
+		starsKey := "Sem recrutamento"
+		if entry.Stars != nil {
+			starsKey = fmt.Sprintf("%d estrelas", *entry.Stars)
+		}
+		summarize(seasons, &seasonOrder, fmt.Sprint(entry.Year), entry)
+		summarize(positions, &positionOrder, entry.Position, entry)
+		summarize(stars, &starsOrder, starsKey, entry)
+
+		if entry.Round != nil && *entry.Round > 0 {
+			report.Picks = append(report.Picks, entry)
+		}
+	}
+
+	for _, key := range seasonOrder {
+		report.BySeason = append(report.BySeason, *seasons[key])
+	}
+	for _, key := range positionOrder {
+		report.ByPosition = append(report.ByPosition, *positions[key])
+	}
+	for _, key := range starsOrder {
+		report.ByStars = append(report.ByStars, *stars[key])
+	}
+	return report, nil
+}
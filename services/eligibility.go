@@ -0,0 +1,197 @@
+package services
+
+import (
+	"database/sql"
+	"dynastyTracker/database"
+	"dynastyTracker/models"
+	"fmt"
+	"strings"
+)
+
+// Limite de jogos por temporada para manter o redshirt (regra dos quatro jogos)
+const maxRedshirtGames = 4
+
+// classYearAliases mapeia as formas livres usadas antes do enum para o valor padrão
+var classYearAliases = map[string]models.ClassYear{
+	"FR":                 models.ClassFreshman,
+	"FRESHMAN":           models.ClassFreshman,
+	"RS FR":              models.ClassRedshirtFreshman,
+	"RSFR":               models.ClassRedshirtFreshman,
+	"REDSHIRT FRESHMAN":  models.ClassRedshirtFreshman,
+	"SO":                 models.ClassSophomore,
+	"SOPHOMORE":          models.ClassSophomore,
+	"RS SO":              models.ClassRedshirtSophomore,
+	"RSSO":               models.ClassRedshirtSophomore,
+	"REDSHIRT SOPHOMORE": models.ClassRedshirtSophomore,
+	"JR":                 models.ClassJunior,
+	"JUNIOR":             models.ClassJunior,
+	"RS JR":              models.ClassRedshirtJunior,
+	"RSJR":               models.ClassRedshirtJunior,
+	"REDSHIRT JUNIOR":    models.ClassRedshirtJunior,
+	"SR":                 models.ClassSenior,
+	"SENIOR":             models.ClassSenior,
+	"RS SR":              models.ClassRedshirtSenior,
+	"RSSR":               models.ClassRedshirtSenior,
+	"REDSHIRT SENIOR":    models.ClassRedshirtSenior,
+}
+
+// ParseClassYear converte um ano acadêmico em texto livre para o enum ClassYear
+func ParseClassYear(value string) (models.ClassYear, error) {
+	normalized := strings.ToUpper(strings.TrimSpace(strings.ReplaceAll(value, ".", "")))
+	normalized = strings.Join(strings.Fields(normalized), " ")
+	if classYear, ok := classYearAliases[normalized]; ok {
+		return classYear, nil
+	}
+	return "", fmt.Errorf("ano acadêmico inválido: %q", value)
+}
+
+// isRedshirtClass indica se o ano acadêmico já é uma variação redshirt
+func isRedshirtClass(classYear models.ClassYear) bool {
+	return strings.HasPrefix(string(classYear), "RS ")
+}
+
+// nextClassYear calcula o ano acadêmico da próxima temporada. Quando o jogador
+// usou o redshirt na temporada, ele mantém o ano e ganha o prefixo RS. O
+// segundo retorno indica que a elegibilidade foi esgotada.
+func nextClassYear(classYear models.ClassYear, redshirtedThisSeason bool) (models.ClassYear, bool) {
+	if redshirtedThisSeason && !isRedshirtClass(classYear) {
+		return models.ClassYear("RS " + string(classYear)), false
+	}
+
+	switch classYear {
+	case models.ClassFreshman:
+		return models.ClassSophomore, false
+	case models.ClassRedshirtFreshman:
+		return models.ClassRedshirtSophomore, false
+	case models.ClassSophomore:
+		return models.ClassJunior, false
+	case models.ClassRedshirtSophomore:
+		return models.ClassRedshirtJunior, false
+	case models.ClassJunior:
+		return models.ClassSenior, false
+	case models.ClassRedshirtJunior:
+		return models.ClassRedshirtSenior, false
+	}
+	return classYear, true
+}
+
+// remainingEligibility conta as temporadas que restam após a temporada atual. Quem ainda não
+// usou o redshirt tem uma temporada a mais no prazo de cinco anos; quem usou nesta temporada
+// só recebe o prefixo RS na virada e também conta essa temporada a mais
+func remainingEligibility(classYear models.ClassYear) int {
+	var remaining int
+	switch strings.TrimPrefix(string(classYear), "RS ") {
+	case "FR":
+		remaining = 3
+	case "SO":
+		remaining = 2
+	case "JR":
+		remaining = 1
+	}
+	if !isRedshirtClass(classYear) {
+		remaining++
+	}
+	return remaining
+}
+
+// GetGamesPlayedInSeason conta os jogos com estatísticas registradas para o jogador na temporada
+func GetGamesPlayedInSeason(playerID int, year int) (int, error) {
+	var games int
+	err := database.DB.QueryRow(`
+        SELECT COUNT(DISTINCT g.schedule_id)
+        FROM playergamestats g
+        JOIN schedule s ON g.schedule_id = s.id
+        WHERE g.player_id = ? AND s.year = ?
+    `, playerID, year).Scan(&games)
+	if err != nil {
+		fmt.Printf("Erro ao contar jogos do jogador: %v\n", err)
+		return 0, err
+	}
+	return games, nil
+}
+
+type RedshirtEligibility struct {
+	PlayerID     int              `json:"player_id"`
+	Name         string           `json:"name"`
+	Position     string           `json:"position"`
+	ClassYear    models.ClassYear `json:"class_year"`
+	GamesPlayed  int              `json:"games_played"`
+	Redshirted   bool             `json:"redshirted"`
+	RedshirtYear *int             `json:"redshirt_year"`
+	Eligible     bool             `json:"eligible"`
+}
+
+// GetRedshirtEligibility lista os jogadores e se ainda podem usar o redshirt na temporada
+func GetRedshirtEligibility(year int, teamID int) ([]RedshirtEligibility, error) {
+	query := `
+        SELECT p.player_id, p.name, p.position, p.class_year, COALESCE(gp.games, 0) AS games_played,
+               p.redshirted, p.redshirt_year
+        FROM players p
+        LEFT JOIN (
+            SELECT g.player_id, COUNT(DISTINCT g.schedule_id) AS games
+            FROM playergamestats g
+            JOIN schedule s ON g.schedule_id = s.id
+            WHERE s.year = ?
+            GROUP BY g.player_id
+        ) gp ON gp.player_id = p.player_id
+        WHERE p.active = 1 AND p.eligibility_exhausted = 0
+    `
+	args := []interface{}{year}
+	if teamID > 0 {
+		query += " AND p.team_id = ?"
+		args = append(args, teamID)
+	}
+	query += " ORDER BY p.position, p.name"
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		fmt.Printf("Erro ao executar consulta: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var report []RedshirtEligibility
+	for rows.Next() {
+		var item RedshirtEligibility
+		err := rows.Scan(&item.PlayerID, &item.Name, &item.Position, &item.ClassYear, &item.GamesPlayed,
+			&item.Redshirted, &item.RedshirtYear)
+		if err != nil {
+			fmt.Printf("Erro ao escanear resultados: %v\n", err)
+			return nil, err
+		}
+		usedThisSeason := item.RedshirtYear != nil && *item.RedshirtYear == year
+		item.Eligible = item.GamesPlayed <= maxRedshirtGames && (!item.Redshirted || usedThisSeason)
+		report = append(report, item)
+	}
+
+	return report, nil
+}
+
+// SetRedshirt marca o jogador como redshirt na temporada, respeitando a regra dos quatro jogos
+func SetRedshirt(playerID int, year int) error {
+	var redshirted bool
+	var redshirtYear sql.NullInt64
+	err := database.DB.QueryRow("SELECT redshirted, redshirt_year FROM players WHERE player_id = ?", playerID).
+		Scan(&redshirted, &redshirtYear)
+	if err != nil {
+		return err
+	}
+	if redshirted && (!redshirtYear.Valid || int(redshirtYear.Int64) != year) {
+		return fmt.Errorf("o jogador já utilizou o redshirt")
+	}
+
+	games, err := GetGamesPlayedInSeason(playerID, year)
+	if err != nil {
+		return err
+	}
+	if games > maxRedshirtGames {
+		return fmt.Errorf("o jogador disputou %d jogos em %d e não pode usar o redshirt", games, year)
+	}
+
+	_, err = database.DB.Exec("UPDATE players SET redshirted = 1, redshirt_year = ? WHERE player_id = ?", year, playerID)
+	if err != nil {
+		fmt.Printf("Erro ao marcar redshirt: %v\n", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package services
+
+import (
+	"dynastyTracker/models"
+	"testing"
+)
+
+func TestRemainingEligibility(t *testing.T) {
+	tests := []struct {
+		classYear models.ClassYear
+		want      int
+	}{
+		// Sem prefixo RS: ainda pode usar o redshirt, ou usou nesta temporada e recebe o RS na virada
+		{models.ClassFreshman, 4},
+		{models.ClassSophomore, 3},
+		{models.ClassJunior, 2},
+		{models.ClassSenior, 1},
+		// Redshirt já usado em temporada anterior
+		{models.ClassRedshirtFreshman, 3},
+		{models.ClassRedshirtSophomore, 2},
+		{models.ClassRedshirtJunior, 1},
+		{models.ClassRedshirtSenior, 0},
+	}
+	for _, tt := range tests {
+		if got := remainingEligibility(tt.classYear); got != tt.want {
+			t.Errorf("remainingEligibility(%q) = %d, want %d", tt.classYear, got, tt.want)
+		}
+	}
+}
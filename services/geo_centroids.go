@@ -0,0 +1,113 @@
+package services
+
+// Centroides aproximados (longitude, latitude) usados no mapa de recrutamento, embutidos no
+// código para que o mapa funcione sem chamadas de rede. Cidades fora da lista caem no centroide
+// do estado
+
+type geoPoint struct {
+	Lon float64
+	Lat float64
+}
+
+var stateNames = map[string]string{
+	"ALABAMA": "AL", "ALASKA": "AK", "ARIZONA": "AZ", "ARKANSAS": "AR", "CALIFORNIA": "CA",
+	"COLORADO": "CO", "CONNECTICUT": "CT", "DELAWARE": "DE", "DISTRICT OF COLUMBIA": "DC", "FLORIDA": "FL",
+	"GEORGIA": "GA", "HAWAII": "HI", "IDAHO": "ID", "ILLINOIS": "IL", "INDIANA": "IN",
+	"IOWA": "IA", "KANSAS": "KS", "KENTUCKY": "KY", "LOUISIANA": "LA", "MAINE": "ME",
+	"MARYLAND": "MD", "MASSACHUSETTS": "MA", "MICHIGAN": "MI", "MINNESOTA": "MN", "MISSISSIPPI": "MS",
+	"MISSOURI": "MO", "MONTANA": "MT", "NEBRASKA": "NE", "NEVADA": "NV", "NEW HAMPSHIRE": "NH",
+	"NEW JERSEY": "NJ", "NEW MEXICO": "NM", "NEW YORK": "NY", "NORTH CAROLINA": "NC", "NORTH DAKOTA": "ND",
+	"OHIO": "OH", "OKLAHOMA": "OK", "OREGON": "OR", "PENNSYLVANIA": "PA", "RHODE ISLAND": "RI",
+	"SOUTH CAROLINA": "SC", "SOUTH DAKOTA": "SD", "TENNESSEE": "TN", "TEXAS": "TX", "UTAH": "UT",
+	"VERMONT": "VT", "VIRGINIA": "VA", "WASHINGTON": "WA", "WEST VIRGINIA": "WV", "WISCONSIN": "WI",
+	"WYOMING": "WY",
+}
+
+var stateCentroids = map[string]geoPoint{
+	"AL": {-86.83, 32.79}, "AK": {-152.28, 64.07}, "AZ": {-111.66, 34.29}, "AR": {-92.44, 34.90},
+	"CA": {-119.45, 37.18}, "CO": {-105.55, 38.99}, "CT": {-72.73, 41.62}, "DE": {-75.51, 38.99},
+	"DC": {-77.02, 38.90}, "FL": {-82.46, 28.63}, "GA": {-83.44, 32.65}, "HI": {-156.37, 20.29},
+	"ID": {-114.61, 44.35}, "IL": {-89.20, 40.04}, "IN": {-86.28, 39.89}, "IA": {-93.50, 42.08},
+	"KS": {-98.38, 38.49}, "KY": {-85.30, 37.53}, "LA": {-91.96, 31.07}, "ME": {-69.24, 45.37},
+	"MD": {-76.79, 39.06}, "MA": {-71.81, 42.26}, "MI": {-85.41, 44.35}, "MN": {-94.31, 46.28},
+	"MS": {-89.66, 32.74}, "MO": {-92.46, 38.36}, "MT": {-109.63, 47.05}, "NE": {-99.79, 41.54},
+	"NV": {-116.65, 39.33}, "NH": {-71.58, 43.68}, "NJ": {-74.67, 40.19}, "NM": {-106.11, 34.41},
+	"NY": {-75.53, 42.95}, "NC": {-79.39, 35.56}, "ND": {-100.47, 47.45}, "OH": {-82.79, 40.29},
+	"OK": {-97.49, 35.59}, "OR": {-120.56, 43.93}, "PA": {-77.80, 40.88}, "RI": {-71.56, 41.68},
+	"SC": {-80.90, 33.92}, "SD": {-100.23, 44.44}, "TN": {-86.34, 35.86}, "TX": {-99.33, 31.48},
+	"UT": {-111.68, 39.32}, "VT": {-72.67, 44.07}, "VA": {-78.85, 37.52}, "WA": {-120.45, 47.38},
+	"WV": {-80.62, 38.64}, "WI": {-89.99, 44.62}, "WY": {-107.55, 42.99},
+}
+
+// Cidades indexadas por "CIDADE|UF" (maiúsculas, sem acentos)
+var cityCentroids = map[string]geoPoint{
+	// Texas
+	"HOUSTON|TX": {-95.37, 29.76}, "DALLAS|TX": {-96.80, 32.78}, "AUSTIN|TX": {-97.74, 30.27},
+	"SAN ANTONIO|TX": {-98.49, 29.42}, "FORT WORTH|TX": {-97.33, 32.76}, "EL PASO|TX": {-106.49, 31.76},
+	"ARLINGTON|TX": {-97.11, 32.74}, "PLANO|TX": {-96.70, 33.02}, "ALLEN|TX": {-96.67, 33.10},
+	"DUNCANVILLE|TX": {-96.91, 32.65}, "DESOTO|TX": {-96.86, 32.59}, "KATY|TX": {-95.82, 29.79},
+	"SOUTHLAKE|TX": {-97.13, 32.94}, "CYPRESS|TX": {-95.70, 29.97}, "LUBBOCK|TX": {-101.86, 33.58},
+	"WACO|TX": {-97.15, 31.55}, "BEAUMONT|TX": {-94.10, 30.08}, "TYLER|TX": {-95.30, 32.35},
+	"CORPUS CHRISTI|TX": {-97.40, 27.80}, "MANSFIELD|TX": {-97.14, 32.56}, "DENTON|TX": {-97.13, 33.21},
+	// Flórida
+	"MIAMI|FL": {-80.19, 25.76}, "FORT LAUDERDALE|FL": {-80.14, 26.12}, "TAMPA|FL": {-82.46, 27.95},
+	"ORLANDO|FL": {-81.38, 28.54}, "JACKSONVILLE|FL": {-81.66, 30.33}, "TALLAHASSEE|FL": {-84.28, 30.44},
+	"GAINESVILLE|FL": {-82.32, 29.65}, "BRADENTON|FL": {-82.57, 27.50}, "PLANTATION|FL": {-80.23, 26.13},
+	"MIRAMAR|FL": {-80.23, 25.99}, "PENSACOLA|FL": {-87.22, 30.42}, "WEST PALM BEACH|FL": {-80.05, 26.72},
+	"ST. PETERSBURG|FL": {-82.64, 27.77}, "LAKELAND|FL": {-81.95, 28.04}, "BELLE GLADE|FL": {-80.67, 26.68},
+	"HOMESTEAD|FL": {-80.48, 25.47}, "SARASOTA|FL": {-82.53, 27.34},
+	// Geórgia
+	"ATLANTA|GA": {-84.39, 33.75}, "SAVANNAH|GA": {-81.10, 32.08}, "AUGUSTA|GA": {-81.97, 33.47},
+	"COLUMBUS|GA": {-84.99, 32.46}, "MACON|GA": {-83.63, 32.84}, "ATHENS|GA": {-83.38, 33.96},
+	"BUFORD|GA": {-84.00, 34.12}, "VALDOSTA|GA": {-83.28, 30.83}, "MARIETTA|GA": {-84.55, 33.95},
+	"SUWANEE|GA": {-84.07, 34.05}, "KENNESAW|GA": {-84.62, 34.02},
+	// Califórnia
+	"LOS ANGELES|CA": {-118.24, 34.05}, "SAN DIEGO|CA": {-117.16, 32.72}, "SAN FRANCISCO|CA": {-122.42, 37.77},
+	"SACRAMENTO|CA": {-121.49, 38.58}, "OAKLAND|CA": {-122.27, 37.80}, "SAN JOSE|CA": {-121.89, 37.34},
+	"FRESNO|CA": {-119.79, 36.74}, "LONG BEACH|CA": {-118.19, 33.77}, "SANTA ANA|CA": {-117.87, 33.75},
+	"MISSION VIEJO|CA": {-117.66, 33.60}, "CORONA|CA": {-117.57, 33.88}, "BAKERSFIELD|CA": {-119.02, 35.37},
+	"GARDENA|CA": {-118.31, 33.89}, "CONCORD|CA": {-122.03, 37.98}, "RIVERSIDE|CA": {-117.40, 33.98},
+	// Sudeste
+	"BIRMINGHAM|AL": {-86.80, 33.52}, "MOBILE|AL": {-88.04, 30.69}, "MONTGOMERY|AL": {-86.30, 32.38},
+	"HUNTSVILLE|AL": {-86.59, 34.73}, "TUSCALOOSA|AL": {-87.57, 33.21}, "AUBURN|AL": {-85.48, 32.61},
+	"NEW ORLEANS|LA": {-90.07, 29.95}, "BATON ROUGE|LA": {-91.15, 30.45}, "SHREVEPORT|LA": {-93.75, 32.53},
+	"LAFAYETTE|LA": {-92.02, 30.22}, "MONROE|LA": {-92.12, 32.51},
+	"JACKSON|MS": {-90.18, 32.30}, "HATTIESBURG|MS": {-89.29, 31.33}, "GULFPORT|MS": {-89.09, 30.37},
+	"STARKVILLE|MS": {-88.82, 33.45}, "OXFORD|MS": {-89.52, 34.37},
+	"COLUMBIA|SC": {-81.03, 34.00}, "CHARLESTON|SC": {-79.93, 32.78}, "GREENVILLE|SC": {-82.39, 34.85},
+	"CHARLOTTE|NC": {-80.84, 35.23}, "RALEIGH|NC": {-78.64, 35.78}, "DURHAM|NC": {-78.90, 35.99},
+	"GREENSBORO|NC": {-79.79, 36.07}, "WINSTON-SALEM|NC": {-80.24, 36.10},
+	"NASHVILLE|TN": {-86.78, 36.16}, "MEMPHIS|TN": {-90.05, 35.15}, "KNOXVILLE|TN": {-83.92, 35.96},
+	"CHATTANOOGA|TN": {-85.31, 35.05},
+	"LOUISVILLE|KY":  {-85.76, 38.25}, "LEXINGTON|KY": {-84.50, 38.04},
+	"LITTLE ROCK|AR": {-92.29, 34.75}, "FAYETTEVILLE|AR": {-94.16, 36.06},
+	"RICHMOND|VA": {-77.44, 37.54}, "NORFOLK|VA": {-76.29, 36.85}, "VIRGINIA BEACH|VA": {-75.98, 36.85},
+	"CHESAPEAKE|VA": {-76.29, 36.77},
+	"BALTIMORE|MD":  {-76.61, 39.29}, "WASHINGTON|DC": {-77.04, 38.91},
+	// Meio-oeste
+	"COLUMBUS|OH": {-83.00, 39.96}, "CLEVELAND|OH": {-81.69, 41.50}, "CINCINNATI|OH": {-84.51, 39.10},
+	"TOLEDO|OH": {-83.56, 41.65}, "AKRON|OH": {-81.52, 41.08}, "MASSILLON|OH": {-81.52, 40.80},
+	"CHICAGO|IL": {-87.63, 41.88}, "PEORIA|IL": {-89.59, 40.69}, "CHAMPAIGN|IL": {-88.24, 40.12},
+	"DETROIT|MI": {-83.05, 42.33}, "GRAND RAPIDS|MI": {-85.67, 42.96}, "ANN ARBOR|MI": {-83.74, 42.28},
+	"LANSING|MI":      {-84.56, 42.73},
+	"INDIANAPOLIS|IN": {-86.16, 39.77}, "FORT WAYNE|IN": {-85.14, 41.08},
+	"ST. LOUIS|MO": {-90.20, 38.63}, "KANSAS CITY|MO": {-94.58, 39.10}, "COLUMBIA|MO": {-92.33, 38.95},
+	"MILWAUKEE|WI": {-87.91, 43.04}, "MADISON|WI": {-89.40, 43.07},
+	"MINNEAPOLIS|MN": {-93.27, 44.98}, "ST. PAUL|MN": {-93.09, 44.95},
+	"DES MOINES|IA": {-93.61, 41.59}, "IOWA CITY|IA": {-91.53, 41.66},
+	"OMAHA|NE": {-95.93, 41.26}, "LINCOLN|NE": {-96.70, 40.81},
+	"WICHITA|KS": {-97.34, 37.69}, "KANSAS CITY|KS": {-94.63, 39.11},
+	"OKLAHOMA CITY|OK": {-97.52, 35.47}, "TULSA|OK": {-95.99, 36.15},
+	// Nordeste
+	"PHILADELPHIA|PA": {-75.17, 39.95}, "PITTSBURGH|PA": {-79.99, 40.44}, "HARRISBURG|PA": {-76.88, 40.27},
+	"NEWARK|NJ": {-74.17, 40.74}, "PATERSON|NJ": {-74.17, 40.92}, "CAMDEN|NJ": {-75.12, 39.93},
+	"NEW YORK|NY": {-74.01, 40.71}, "BROOKLYN|NY": {-73.94, 40.68}, "BUFFALO|NY": {-78.88, 42.89},
+	"BOSTON|MA": {-71.06, 42.36}, "HARTFORD|CT": {-72.69, 41.76},
+	// Oeste
+	"PHOENIX|AZ": {-112.07, 33.45}, "TUCSON|AZ": {-110.97, 32.22}, "CHANDLER|AZ": {-111.84, 33.31},
+	"SCOTTSDALE|AZ": {-111.93, 33.49}, "LAS VEGAS|NV": {-115.14, 36.17}, "HENDERSON|NV": {-114.98, 36.04},
+	"DENVER|CO": {-104.99, 39.74}, "SALT LAKE CITY|UT": {-111.89, 40.76}, "PROVO|UT": {-111.66, 40.23},
+	"SEATTLE|WA": {-122.33, 47.61}, "TACOMA|WA": {-122.44, 47.25}, "SPOKANE|WA": {-117.43, 47.66},
+	"PORTLAND|OR": {-122.68, 45.52}, "EUGENE|OR": {-123.09, 44.05},
+	"HONOLULU|HI": {-157.86, 21.31}, "ALBUQUERQUE|NM": {-106.65, 35.08}, "BOISE|ID": {-116.20, 43.62},
+}
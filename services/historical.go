@@ -6,33 +6,51 @@ import (
 	"dynastyTracker/models"
 )
 
-// AddHistoricalRecord adiciona um novo recorde histórico ao banco de dados
+// historicalColumns lista as colunas de historicalrecords na ordem esperada por scanHistoricalRecord
+const historicalColumns = `record_id, school, player_name, year_start, year_end, completions, attempts,
+        completion_percentage, passing_yards, yards_per_attempt, touchdowns, interceptions, passer_rating,
+        rush_attempts, rush_yards, yards_per_carry, rush_tds, receptions, receiving_yards, yards_per_catch,
+        receiving_tds, plays_from_scrimmage, yards_from_scrimmage, avg_yards_per_play, scrimmage_tds, person_id`
+
+func scanHistoricalRecord(row rowScanner) (models.HistoricalRecord, error) {
+	var record models.HistoricalRecord
+	err := row.Scan(
+		&record.RecordID, &record.School, &record.PlayerName, &record.YearStart, &record.YearEnd,
+		&record.Completions, &record.Attempts, &record.CompletionPercentage, &record.PassingYards,
+		&record.YardsPerAttempt, &record.Touchdowns, &record.Interceptions, &record.PasserRating,
+		&record.RushAttempts, &record.RushYards, &record.YardsPerCarry, &record.RushTDs,
+		&record.Receptions, &record.ReceivingYards, &record.YardsPerCatch, &record.ReceivingTDs,
+		&record.PlaysFromScrimmage, &record.YardsFromScrimmage, &record.AvgYardsPerPlay, &record.ScrimmageTDs,
+		&record.PersonID,
+	)
+	return record, err
+}
+
+// AddHistoricalRecord adiciona um novo recorde histórico ao banco de dados. Sem person_id,
+// o recorde ganha uma nova pessoa, que pode ser unida a um jogador com MergePeople
 func AddHistoricalRecord(record models.HistoricalRecord) error {
-	_, err := database.DB.Exec(`INSERT INTO historicalrecords (school, player_name, year_start, year_end, completions, 
+	personID, err := resolvePerson(database.DB, record.PersonID, record.PlayerName)
+	if err != nil {
+		return err
+	}
+
+	_, err = database.DB.Exec(`INSERT INTO historicalrecords (school, player_name, year_start, year_end, completions,
         attempts, completion_percentage, passing_yards, yards_per_attempt, touchdowns, interceptions, passer_rating,
-        rush_attempts, rush_yards, yards_per_carry, rush_tds, receptions, receiving_yards, yards_per_catch, 
-        receiving_tds, plays_from_scrimmage, yards_from_scrimmage, avg_yards_per_play, scrimmage_tds)
-        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+        rush_attempts, rush_yards, yards_per_carry, rush_tds, receptions, receiving_yards, yards_per_catch,
+        receiving_tds, plays_from_scrimmage, yards_from_scrimmage, avg_yards_per_play, scrimmage_tds, person_id)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		record.School, record.PlayerName, record.YearStart, record.YearEnd, record.Completions, record.Attempts,
 		record.CompletionPercentage, record.PassingYards, record.YardsPerAttempt, record.Touchdowns, record.Interceptions,
 		record.PasserRating, record.RushAttempts, record.RushYards, record.YardsPerCarry, record.RushTDs,
 		record.Receptions, record.ReceivingYards, record.YardsPerCatch, record.ReceivingTDs, record.PlaysFromScrimmage,
-		record.YardsFromScrimmage, record.AvgYardsPerPlay, record.ScrimmageTDs)
+		record.YardsFromScrimmage, record.AvgYardsPerPlay, record.ScrimmageTDs, personID)
 	return err
 }
 
 // GetHistoricalRecord obtém um recorde histórico específico pelo ID
 func GetHistoricalRecord(id int) (models.HistoricalRecord, error) {
-	var record models.HistoricalRecord
-	row := database.DB.QueryRow("SELECT * FROM historicalrecords WHERE record_id = ?", id)
-	err := row.Scan(
-		&record.RecordID, &record.School, &record.PlayerName, &record.YearStart, &record.YearEnd,
-		&record.Completions, &record.Attempts, &record.CompletionPercentage, &record.PassingYards,
-		&record.YardsPerAttempt, &record.Touchdowns, &record.Interceptions, &record.PasserRating,
-		&record.RushAttempts, &record.RushYards, &record.YardsPerCarry, &record.RushTDs,
-		&record.Receptions, &record.ReceivingYards, &record.YardsPerCatch, &record.ReceivingTDs,
-		&record.PlaysFromScrimmage, &record.YardsFromScrimmage, &record.AvgYardsPerPlay, &record.ScrimmageTDs,
-	)
+	row := database.DB.QueryRow("SELECT "+historicalColumns+" FROM historicalrecords WHERE record_id = ?", id)
+	record, err := scanHistoricalRecord(row)
 	if err == sql.ErrNoRows {
 		return record, err
 	}
@@ -47,7 +65,7 @@ func DeleteHistoricalRecord(id int) error {
 
 // GetHistoricalRecords retorna todos os recordes históricos
 func GetHistoricalRecords() ([]models.HistoricalRecord, error) {
-	rows, err := database.DB.Query("SELECT * FROM historicalrecords")
+	rows, err := database.DB.Query("SELECT " + historicalColumns + " FROM historicalrecords")
 	if err != nil {
 		return nil, err
 	}
@@ -55,15 +73,7 @@ func GetHistoricalRecords() ([]models.HistoricalRecord, error) {
 
 	var records []models.HistoricalRecord
 	for rows.Next() {
-		var record models.HistoricalRecord
-		err := rows.Scan(
-			&record.RecordID, &record.School, &record.PlayerName, &record.YearStart, &record.YearEnd,
-			&record.Completions, &record.Attempts, &record.CompletionPercentage, &record.PassingYards,
-			&record.YardsPerAttempt, &record.Touchdowns, &record.Interceptions, &record.PasserRating,
-			&record.RushAttempts, &record.RushYards, &record.YardsPerCarry, &record.RushTDs,
-			&record.Receptions, &record.ReceivingYards, &record.YardsPerCatch, &record.ReceivingTDs,
-			&record.PlaysFromScrimmage, &record.YardsFromScrimmage, &record.AvgYardsPerPlay, &record.ScrimmageTDs,
-		)
+		record, err := scanHistoricalRecord(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -77,11 +87,13 @@ func GetHistoricalRecords() ([]models.HistoricalRecord, error) {
 	return records, nil
 }
 
+// UpdateHistoricalRecord atualiza os dados do recorde. A pessoa vinculada só muda por
+// MergePeople/SplitPerson
 func UpdateHistoricalRecord(record models.HistoricalRecord) error {
-	_, err := database.DB.Exec(`UPDATE historicalrecords SET school=?, player_name=?, year_start=?, year_end=?, 
-        completions=?, attempts=?, completion_percentage=?, passing_yards=?, yards_per_attempt=?, touchdowns=?, 
-        interceptions=?, passer_rating=?, rush_attempts=?, rush_yards=?, yards_per_carry=?, rush_tds=?, 
-        receptions=?, receiving_yards=?, yards_per_catch=?, receiving_tds=?, plays_from_scrimmage=?, 
+	_, err := database.DB.Exec(`UPDATE historicalrecords SET school=?, player_name=?, year_start=?, year_end=?,
+        completions=?, attempts=?, completion_percentage=?, passing_yards=?, yards_per_attempt=?, touchdowns=?,
+        interceptions=?, passer_rating=?, rush_attempts=?, rush_yards=?, yards_per_carry=?, rush_tds=?,
+        receptions=?, receiving_yards=?, yards_per_catch=?, receiving_tds=?, plays_from_scrimmage=?,
         yards_from_scrimmage=?, avg_yards_per_play=?, scrimmage_tds=? WHERE record_id=?`,
 		record.School, record.PlayerName, record.YearStart, record.YearEnd, record.Completions, record.Attempts,
 		record.CompletionPercentage, record.PassingYards, record.YardsPerAttempt, record.Touchdowns, record.Interceptions,
@@ -92,7 +104,7 @@ func UpdateHistoricalRecord(record models.HistoricalRecord) error {
 }
 
 func GetHistoricalRecordsWithFilters(school string, playerName string) ([]models.HistoricalRecord, error) {
-	query := "SELECT * FROM historicalrecords WHERE 1=1"
+	query := "SELECT " + historicalColumns + " FROM historicalrecords WHERE 1=1"
 	var args []interface{}
 
 	if school != "" {
@@ -112,13 +124,7 @@ func GetHistoricalRecordsWithFilters(school string, playerName string) ([]models
 
 	var records []models.HistoricalRecord
 	for rows.Next() {
-		var record models.HistoricalRecord
-		err := rows.Scan(&record.RecordID, &record.School, &record.PlayerName, &record.YearStart, &record.YearEnd,
-			&record.Completions, &record.Attempts, &record.CompletionPercentage, &record.PassingYards,
-			&record.YardsPerAttempt, &record.Touchdowns, &record.Interceptions, &record.PasserRating,
-			&record.RushAttempts, &record.RushYards, &record.YardsPerCarry, &record.RushTDs,
-			&record.Receptions, &record.ReceivingYards, &record.YardsPerCatch, &record.ReceivingTDs,
-			&record.PlaysFromScrimmage, &record.YardsFromScrimmage, &record.AvgYardsPerPlay, &record.ScrimmageTDs)
+		record, err := scanHistoricalRecord(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,295 @@
+package services
+
+import (
+	"dynastyTracker/database"
+	"dynastyTracker/models"
+	"fmt"
+	"strings"
+)
+
+const injuryColumns = `i.injury_id, i.player_id, COALESCE(p.name, ''), COALESCE(p.position, ''), i.injury_type, i.body_part,
+        COALESCE(i.injury_date, ''), i.year, i.week, i.estimated_weeks_out, i.return_year, i.return_week`
+
+func scanInjury(row rowScanner) (models.Injury, error) {
+	var injury models.Injury
+	err := row.Scan(&injury.InjuryID, &injury.PlayerID, &injury.PlayerName, &injury.Position, &injury.InjuryType, &injury.BodyPart,
+		&injury.InjuryDate, &injury.Year, &injury.Week, &injury.EstimatedWeeksOut, &injury.ReturnYear, &injury.ReturnWeek)
+	return injury, err
+}
+
+// AddInjury registra uma nova lesão
+func AddInjury(injury models.Injury) error {
+	_, err := database.DB.Exec(`
+        INSERT INTO injuries (player_id, injury_type, body_part, injury_date, year, week, estimated_weeks_out, return_year, return_week)
+        VALUES (?, ?, ?, NULLIF(?, ''), ?, ?, ?, ?, ?)
+    `, injury.PlayerID, injury.InjuryType, injury.BodyPart, injury.InjuryDate, injury.Year, injury.Week,
+		injury.EstimatedWeeksOut, injury.ReturnYear, injury.ReturnWeek)
+	if err != nil {
+		fmt.Printf("Erro ao adicionar lesão: %v\n", err)
+		return err
+	}
+	return nil
+}
+
+// UpdateInjury atualiza uma lesão, incluindo o retorno real do jogador
+func UpdateInjury(injury models.Injury) error {
+	_, err := database.DB.Exec(`
+        UPDATE injuries SET player_id=?, injury_type=?, body_part=?, injury_date=NULLIF(?, ''), year=?, week=?,
+            estimated_weeks_out=?, return_year=?, return_week=?
+        WHERE injury_id=?
+    `, injury.PlayerID, injury.InjuryType, injury.BodyPart, injury.InjuryDate, injury.Year, injury.Week,
+		injury.EstimatedWeeksOut, injury.ReturnYear, injury.ReturnWeek, injury.InjuryID)
+	return err
+}
+
+// GetInjury obtém uma lesão específica pelo ID
+func GetInjury(id int) (models.Injury, error) {
+	row := database.DB.QueryRow(`SELECT `+injuryColumns+`
+        FROM injuries i
+        LEFT JOIN players p ON p.player_id = i.player_id
+        WHERE i.injury_id = ?`, id)
+	return scanInjury(row)
+}
+
+// DeleteInjury exclui uma lesão pelo ID
+func DeleteInjury(id int) error {
+	_, err := database.DB.Exec("DELETE FROM injuries WHERE injury_id = ?", id)
+	return err
+}
+
+// GetInjuriesWithFilters lista as lesões filtrando por jogador, time e temporada. O time é o do
+// jogador na temporada consultada (ou na da lesão, sem temporada), não o atual
+func GetInjuriesWithFilters(playerID int, teamID int, year int) ([]models.Injury, error) {
+	query := `SELECT ` + injuryColumns + `
+        FROM injuries i
+        LEFT JOIN players p ON p.player_id = i.player_id
+        WHERE 1=1`
+	var args []interface{}
+
+	if playerID > 0 {
+		query += " AND i.player_id = ?"
+		args = append(args, playerID)
+	}
+	if teamID > 0 {
+		if year > 0 {
+			query += " AND " + playerTeamInYearSQL("i.player_id", "?") + " = ?"
+			args = append(args, year, year, teamID)
+		} else {
+			query += " AND " + playerTeamInYearSQL("i.player_id", "i.year") + " = ?"
+			args = append(args, teamID)
+		}
+	}
+	if year > 0 {
+		// Inclui lesões de temporadas anteriores que ainda afetam a temporada
+		query += " AND (i.year = ? OR (i.year < ? AND (i.return_year IS NULL OR i.return_year >= ?)))"
+		args = append(args, year, year, year)
+	}
+	query += " ORDER BY i.year, i.week"
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		fmt.Printf("Erro ao executar consulta: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var injuries []models.Injury
+	for rows.Next() {
+		injury, err := scanInjury(rows)
+		if err != nil {
+			fmt.Printf("Erro ao escanear resultados: %v\n", err)
+			return nil, err
+		}
+		injuries = append(injuries, injury)
+	}
+	return injuries, nil
+}
+
+// isInjuryActive indica se a lesão deixa o jogador indisponível na temporada e semana.
+// Sem retorno registrado, vale a estimativa de semanas fora dentro da temporada da lesão.
+func isInjuryActive(injury models.Injury, year int, week int) bool {
+	if year < injury.Year || (year == injury.Year && week < injury.Week) {
+		return false
+	}
+	if injury.ReturnWeek != nil {
+		returnYear := injury.Year
+		if injury.ReturnYear != nil {
+			returnYear = *injury.ReturnYear
+		}
+		return year < returnYear || (year == returnYear && week < *injury.ReturnWeek)
+	}
+	return year == injury.Year && week < injury.Week+injury.EstimatedWeeksOut
+}
+
+// getActiveInjury retorna a lesão ativa do jogador na semana, se houver
+func getActiveInjury(playerID int, year int, week int) (*models.Injury, error) {
+	injuries, err := GetInjuriesWithFilters(playerID, 0, year)
+	if err != nil {
+		return nil, err
+	}
+	for _, injury := range injuries {
+		if isInjuryActive(injury, year, week) {
+			return &injury, nil
+		}
+	}
+	return nil, nil
+}
+
+type UnavailablePlayer struct {
+	PlayerID          int    `json:"player_id"`
+	Name              string `json:"name"`
+	Position          string `json:"position"`
+	InjuryID          int    `json:"injury_id"`
+	InjuryType        string `json:"injury_type"`
+	BodyPart          string `json:"body_part"`
+	InjuryYear        int    `json:"injury_year"`
+	InjuryWeek        int    `json:"injury_week"`
+	EstimatedWeeksOut int    `json:"estimated_weeks_out"`
+	ReturnWeek        *int   `json:"return_week"`
+}
+
+// GetUnavailablePlayers lista os jogadores do time indisponíveis por lesão na semana
+func GetUnavailablePlayers(teamID int, year int, week int) ([]UnavailablePlayer, error) {
+	injuries, err := GetInjuriesWithFilters(0, teamID, year)
+	if err != nil {
+		return nil, err
+	}
+
+	var unavailable []UnavailablePlayer
+	seen := make(map[int]bool)
+	for _, injury := range injuries {
+		if !isInjuryActive(injury, year, week) || seen[injury.PlayerID] {
+			continue
+		}
+		seen[injury.PlayerID] = true
+
+		unavailable = append(unavailable, UnavailablePlayer{
+			PlayerID:          injury.PlayerID,
+			Name:              injury.PlayerName,
+			Position:          injury.Position,
+			InjuryID:          injury.InjuryID,
+			InjuryType:        injury.InjuryType,
+			BodyPart:          injury.BodyPart,
+			InjuryYear:        injury.Year,
+			InjuryWeek:        injury.Week,
+			EstimatedWeeksOut: injury.EstimatedWeeksOut,
+			ReturnWeek:        injury.ReturnWeek,
+		})
+	}
+	return unavailable, nil
+}
+
+type GamesMissedReport struct {
+	PlayerID      int    `json:"player_id"`
+	Name          string `json:"name"`
+	Position      string `json:"position"`
+	Year          int    `json:"year"`
+	Injuries      int    `json:"injuries"`
+	GamesMissed   int    `json:"games_missed"`
+	TeamGames     int    `json:"team_games"`
+	PlayedInjured int    `json:"played_injured"` // Jogos com estatísticas durante a lesão
+}
+
+// GetGamesMissedReport calcula, por jogador e temporada, os jogos perdidos por lesão. Só contam
+// jogos já disputados (com resultado)
+func GetGamesMissedReport(teamID int, playerID int, year int) ([]GamesMissedReport, error) {
+	injuries, err := GetInjuriesWithFilters(playerID, teamID, year)
+	if err != nil {
+		return nil, err
+	}
+
+	// Agrupar lesões por jogador
+	injuriesByPlayer := make(map[int][]models.Injury)
+	var playerOrder []int
+	for _, injury := range injuries {
+		if _, ok := injuriesByPlayer[injury.PlayerID]; !ok {
+			playerOrder = append(playerOrder, injury.PlayerID)
+		}
+		injuriesByPlayer[injury.PlayerID] = append(injuriesByPlayer[injury.PlayerID], injury)
+	}
+
+	if len(playerOrder) == 0 {
+		return nil, nil
+	}
+
+	// Jogos já disputados pelo time de cada jogador em cada temporada, com a indicação de que ele jogou
+	query := `
+        SELECT p.player_id, p.name, p.position, s.year, s.week,
+            EXISTS (SELECT 1 FROM playergamestats g WHERE g.schedule_id = s.id AND g.player_id = p.player_id) AS played
+        FROM players p
+        JOIN schedule s ON s.result IS NOT NULL AND s.result <> ''
+        WHERE s.team_id = ` + playerTeamInYearSQL("p.player_id", "s.year") + `
+          AND p.player_id IN (` + strings.TrimSuffix(strings.Repeat("?, ", len(playerOrder)), ", ") + `)`
+	args := make([]interface{}, 0, len(playerOrder)+2)
+	for _, id := range playerOrder {
+		args = append(args, id)
+	}
+	if teamID > 0 {
+		query += " AND s.team_id = ?"
+		args = append(args, teamID)
+	}
+	if year > 0 {
+		query += " AND s.year = ?"
+		args = append(args, year)
+	}
+	query += " ORDER BY p.player_id, s.year, s.week"
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		fmt.Printf("Erro ao executar consulta: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	seasons := make(map[int]map[int]*GamesMissedReport)
+	seasonOrder := make(map[int][]int)
+	for rows.Next() {
+		var id, gameYear, gameWeek int
+		var name, position string
+		var played bool
+		if err := rows.Scan(&id, &name, &position, &gameYear, &gameWeek, &played); err != nil {
+			fmt.Printf("Erro ao escanear resultados: %v\n", err)
+			return nil, err
+		}
+		if seasons[id] == nil {
+			seasons[id] = make(map[int]*GamesMissedReport)
+		}
+		season, ok := seasons[id][gameYear]
+		if !ok {
+			season = &GamesMissedReport{PlayerID: id, Name: name, Position: position, Year: gameYear}
+			seasons[id][gameYear] = season
+			seasonOrder[id] = append(seasonOrder[id], gameYear)
+		}
+		season.TeamGames++
+
+		for _, injury := range injuriesByPlayer[id] {
+			if isInjuryActive(injury, gameYear, gameWeek) {
+				if played {
+					season.PlayedInjured++
+				} else {
+					season.GamesMissed++
+				}
+				break
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var reports []GamesMissedReport
+	for _, id := range playerOrder {
+		for _, injury := range injuriesByPlayer[id] {
+			if season, ok := seasons[id][injury.Year]; ok {
+				season.Injuries++
+			}
+		}
+		for _, gameYear := range seasonOrder[id] {
+			if seasons[id][gameYear].GamesMissed > 0 || seasons[id][gameYear].Injuries > 0 {
+				reports = append(reports, *seasons[id][gameYear])
+			}
+		}
+	}
+
+	return reports, nil
+}
@@ -0,0 +1,448 @@
+package services
+
+import (
+	"database/sql"
+	"dynastyTracker/database"
+	"dynastyTracker/models"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrJerseyPlayerNotFound indica que o jogador da numeração não existe
+var ErrJerseyPlayerNotFound = errors.New("jogador não encontrado")
+
+// JerseyRuleError indica que o número foi recusado pelas regras de numeração
+type JerseyRuleError struct {
+	Reason string
+}
+
+func (e *JerseyRuleError) Error() string {
+	return e.Reason
+}
+
+// Lados do campo para a regra de números repetidos
+const (
+	sideOffense = "offense"
+	sideDefense = "defense"
+	sideSpecial = "special"
+)
+
+var offensivePositions = map[string]bool{"QB": true, "HB": true, "FB": true, "WR": true, "TE": true,
+	"LT": true, "LG": true, "C": true, "RG": true, "RT": true}
+var offensiveLinemen = map[string]bool{"LT": true, "LG": true, "C": true, "RG": true, "RT": true}
+var specialistPositions = map[string]bool{"K": true, "P": true}
+
+// Faixas preferidas por posição, usadas na ordem das sugestões
+var preferredJerseyRanges = map[string][][2]int{
+	"QB":   {{1, 19}},
+	"HB":   {{1, 49}},
+	"FB":   {{30, 49}},
+	"WR":   {{1, 19}, {80, 89}},
+	"TE":   {{80, 89}, {40, 49}},
+	"LT":   {{50, 79}},
+	"LG":   {{50, 79}},
+	"C":    {{50, 79}},
+	"RG":   {{50, 79}},
+	"RT":   {{50, 79}},
+	"LEDG": {{90, 99}, {40, 59}},
+	"REDG": {{90, 99}, {40, 59}},
+	"DT":   {{90, 99}, {50, 79}},
+	"SAM":  {{40, 59}},
+	"MIKE": {{40, 59}},
+	"WILL": {{40, 59}},
+	"CB":   {{1, 39}},
+	"FS":   {{1, 49}},
+	"SS":   {{1, 49}},
+	"K":    {{1, 49}, {90, 99}},
+	"P":    {{1, 49}, {90, 99}},
+}
+
+func positionSide(position string) string {
+	switch {
+	case specialistPositions[position]:
+		return sideSpecial
+	case offensivePositions[position]:
+		return sideOffense
+	default:
+		return sideDefense
+	}
+}
+
+// validateJerseyNumber aplica as regras de numeração por posição: linha ofensiva usa 50-79,
+// e recebedores elegíveis (QB, HB, FB, WR, TE) não podem usar essa faixa
+func validateJerseyNumber(position string, number int) error {
+	if number < 0 || number > 99 {
+		return fmt.Errorf("número inválido: %d (use 0 a 99)", number)
+	}
+	ineligibleRange := number >= 50 && number <= 79
+	if offensiveLinemen[position] && !ineligibleRange {
+		return fmt.Errorf("jogadores de linha ofensiva (%s) usam números de 50 a 79", position)
+	}
+	if offensivePositions[position] && !offensiveLinemen[position] && ineligibleRange {
+		return fmt.Errorf("recebedores elegíveis (%s) não podem usar números de 50 a 79", position)
+	}
+	return nil
+}
+
+// jerseyConflict diz por que os jogadores não podem dividir o mesmo número. Só são permitidos
+// dois jogadores por número, um do ataque e um da defesa; especialistas não dividem número
+func jerseyConflict(holders []models.JerseyAssignment) string {
+	if len(holders) < 2 {
+		return ""
+	}
+	if len(holders) > 2 {
+		return fmt.Sprintf("%d jogadores com o mesmo número", len(holders))
+	}
+	first, second := positionSide(holders[0].Position), positionSide(holders[1].Position)
+	if first == sideSpecial || second == sideSpecial {
+		return "especialistas (K/P) não podem dividir número"
+	}
+	if first == second {
+		return fmt.Sprintf("dois jogadores do mesmo lado (%s) com o mesmo número", first)
+	}
+	return ""
+}
+
+func isRetiredNumber(teamID int, number int) (bool, error) {
+	var count int
+	err := database.DB.QueryRow("SELECT COUNT(*) FROM retired_numbers WHERE team_id = ? AND number = ?", teamID, number).Scan(&count)
+	return count > 0, err
+}
+
+// GetJerseyNumbers lista os números do time na temporada
+func GetJerseyNumbers(teamID int, year int) ([]models.JerseyAssignment, error) {
+	return getJerseyNumbers(database.DB, teamID, year)
+}
+
+func getJerseyNumbers(q sqlQueryer, teamID int, year int) ([]models.JerseyAssignment, error) {
+	rows, err := q.Query(`
+        SELECT j.player_id, p.name, p.position, j.team_id, j.year, j.number
+        FROM player_jerseys j
+        JOIN players p ON p.player_id = j.player_id
+        WHERE j.team_id = ? AND j.year = ?
+        ORDER BY j.number, p.name
+    `, teamID, year)
+	if err != nil {
+		fmt.Printf("Erro ao executar consulta: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var assignments []models.JerseyAssignment
+	for rows.Next() {
+		var a models.JerseyAssignment
+		if err := rows.Scan(&a.PlayerID, &a.PlayerName, &a.Position, &a.TeamID, &a.Year, &a.Number); err != nil {
+			fmt.Printf("Erro ao escanear resultados: %v\n", err)
+			return nil, err
+		}
+		assignments = append(assignments, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return assignments, nil
+}
+
+// playerTeamInYear descobre o time do jogador na temporada: o do número já registrado no ano,
+// senão o dos jogos com estatísticas no ano, senão o time atual
+func playerTeamInYear(playerID int, year int, currentTeamID int) (int, error) {
+	var teamID int
+	err := database.DB.QueryRow(`
+        SELECT team_id FROM (
+            SELECT j.team_id, 0 AS priority FROM player_jerseys j WHERE j.player_id = ? AND j.year = ?
+            UNION ALL
+            SELECT s.team_id, 1 AS priority FROM playergamestats g JOIN schedule s ON s.id = g.schedule_id
+            WHERE g.player_id = ? AND s.year = ?
+        ) teams
+        ORDER BY priority
+        LIMIT 1
+    `, playerID, year, playerID, year).Scan(&teamID)
+	if err == sql.ErrNoRows {
+		return currentTeamID, nil
+	}
+	return teamID, err
+}
+
+// playerTeamInYearSQL monta a mesma regra de playerTeamInYear como expressão SQL, para consultas
+// que cruzam várias temporadas. player e year são expressões da consulta (ex: "p.player_id", "s.year");
+// a tabela de jogadores precisa estar disponível como p
+func playerTeamInYearSQL(player string, year string) string {
+	return `COALESCE(
+            (SELECT j.team_id FROM player_jerseys j WHERE j.player_id = ` + player + ` AND j.year = ` + year + ` LIMIT 1),
+            (SELECT ts.team_id FROM playergamestats tg JOIN schedule ts ON ts.id = tg.schedule_id
+             WHERE tg.player_id = ` + player + ` AND ts.year = ` + year + ` LIMIT 1),
+            p.team_id)`
+}
+
+// AssignJerseyNumber define o número do jogador na temporada, validando a posição, os números
+// aposentados e quem já usa o número no time daquela temporada. Recusas pelas regras voltam
+// como *JerseyRuleError
+func AssignJerseyNumber(assignment models.JerseyAssignment) error {
+	player, err := GetPlayer(assignment.PlayerID)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("%w: %d", ErrJerseyPlayerNotFound, assignment.PlayerID)
+	}
+	if err != nil {
+		return err
+	}
+	teamID, err := playerTeamInYear(player.PlayerID, assignment.Year, player.TeamID)
+	if err != nil {
+		return err
+	}
+	assignment.TeamID, assignment.Position, assignment.PlayerName = teamID, player.Position, player.Name
+
+	if err := validateJerseyNumber(player.Position, assignment.Number); err != nil {
+		return &JerseyRuleError{Reason: err.Error()}
+	}
+	retired, err := isRetiredNumber(teamID, assignment.Number)
+	if err != nil {
+		return err
+	}
+	if retired {
+		return &JerseyRuleError{Reason: fmt.Sprintf("o número %d está aposentado", assignment.Number)}
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Travar o time serializa as atribuições simultâneas, para que duas requisições não
+	// passem pela checagem de conflito com o mesmo número
+	err = tx.QueryRow("SELECT team_id FROM teams WHERE team_id = ? FOR UPDATE", teamID).Scan(&teamID)
+	if err != nil {
+		return err
+	}
+	current, err := getJerseyNumbers(tx, teamID, assignment.Year)
+	if err != nil {
+		return err
+	}
+	holders := []models.JerseyAssignment{assignment}
+	for _, other := range current {
+		if other.Number == assignment.Number && other.PlayerID != assignment.PlayerID {
+			holders = append(holders, other)
+		}
+	}
+	if conflict := jerseyConflict(holders); conflict != "" {
+		return &JerseyRuleError{Reason: fmt.Sprintf("número %d indisponível: %s", assignment.Number, conflict)}
+	}
+
+	_, err = tx.Exec(`
+        INSERT INTO player_jerseys (player_id, year, team_id, number) VALUES (?, ?, ?, ?)
+        ON DUPLICATE KEY UPDATE team_id = VALUES(team_id), number = VALUES(number)
+    `, assignment.PlayerID, assignment.Year, assignment.TeamID, assignment.Number)
+	if err != nil {
+		fmt.Printf("Erro ao definir número do jogador: %v\n", err)
+		return err
+	}
+	return tx.Commit()
+}
+
+// RemoveJerseyNumber remove o número do jogador na temporada
+func RemoveJerseyNumber(playerID int, year int) error {
+	_, err := database.DB.Exec("DELETE FROM player_jerseys WHERE player_id = ? AND year = ?", playerID, year)
+	return err
+}
+
+type JerseyConflict struct {
+	Number  int                       `json:"number"`
+	Reason  string                    `json:"reason"`
+	Players []models.JerseyAssignment `json:"players"`
+}
+
+// GetJerseyConflicts lista os números da temporada que violam as regras: repetidos no mesmo
+// lado, fora da faixa da posição (ex: após mudança de posição) ou aposentados
+func GetJerseyConflicts(teamID int, year int) ([]JerseyConflict, error) {
+	assignments, err := GetJerseyNumbers(teamID, year)
+	if err != nil {
+		return nil, err
+	}
+	retired, err := GetRetiredNumbers(teamID)
+	if err != nil {
+		return nil, err
+	}
+	retiredSet := make(map[int]bool)
+	for _, r := range retired {
+		retiredSet[r.Number] = true
+	}
+
+	byNumber := make(map[int][]models.JerseyAssignment)
+	var numbers []int
+	for _, a := range assignments {
+		if byNumber[a.Number] == nil {
+			numbers = append(numbers, a.Number)
+		}
+		byNumber[a.Number] = append(byNumber[a.Number], a)
+	}
+
+	var conflicts []JerseyConflict
+	for _, number := range numbers {
+		holders := byNumber[number]
+		if retiredSet[number] {
+			conflicts = append(conflicts, JerseyConflict{Number: number, Reason: "número aposentado", Players: holders})
+		}
+		if reason := jerseyConflict(holders); reason != "" {
+			conflicts = append(conflicts, JerseyConflict{Number: number, Reason: reason, Players: holders})
+		}
+		for _, holder := range holders {
+			if err := validateJerseyNumber(holder.Position, number); err != nil {
+				conflicts = append(conflicts, JerseyConflict{Number: number, Reason: err.Error(), Players: []models.JerseyAssignment{holder}})
+			}
+		}
+	}
+	return conflicts, nil
+}
+
+type JerseySuggestion struct {
+	PlayerID    int    `json:"player_id"`
+	PlayerName  string `json:"player_name"`
+	Position    string `json:"position"`
+	Suggestions []int  `json:"suggestions"`
+}
+
+// suggestJerseyNumbers escolhe números válidos para a posição: primeiro os livres dentro da faixa
+// preferida, depois os que podem ser divididos com um jogador do outro lado
+func suggestJerseyNumbers(position string, taken map[int][]models.JerseyAssignment, retired map[int]bool, count int) []int {
+	var free, shared []int
+	seen := make(map[int]bool)
+
+	consider := func(number int) {
+		if seen[number] || retired[number] || validateJerseyNumber(position, number) != nil {
+			return
+		}
+		seen[number] = true
+		holders := append([]models.JerseyAssignment{{Position: position}}, taken[number]...)
+		switch {
+		case len(taken[number]) == 0:
+			free = append(free, number)
+		case jerseyConflict(holders) == "":
+			shared = append(shared, number)
+		}
+	}
+
+	for _, r := range preferredJerseyRanges[position] {
+		for number := r[0]; number <= r[1]; number++ {
+			consider(number)
+		}
+	}
+	for number := 0; number <= 99; number++ {
+		consider(number)
+	}
+
+	suggestions := append(free, shared...)
+	if len(suggestions) > count {
+		suggestions = suggestions[:count]
+	}
+	return suggestions
+}
+
+// GetJerseySuggestions sugere números para os jogadores ativos do time ainda sem número na
+// temporada, como os recrutas recém-promovidos. As sugestões de um jogador não se repetem
+// como primeira opção de outro
+func GetJerseySuggestions(teamID int, year int) ([]JerseySuggestion, error) {
+	assignments, err := GetJerseyNumbers(teamID, year)
+	if err != nil {
+		return nil, err
+	}
+	retired, err := GetRetiredNumbers(teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	taken := make(map[int][]models.JerseyAssignment)
+	for _, a := range assignments {
+		taken[a.Number] = append(taken[a.Number], a)
+	}
+	retiredSet := make(map[int]bool)
+	for _, r := range retired {
+		retiredSet[r.Number] = true
+	}
+
+	rows, err := database.DB.Query(`
+        SELECT p.player_id, p.name, p.position, p.overall
+        FROM players p
+        WHERE p.team_id = ? AND p.active = 1 AND p.eligibility_exhausted = 0
+          AND NOT EXISTS (SELECT 1 FROM player_jerseys j WHERE j.player_id = p.player_id AND j.year = ?)
+    `, teamID, year)
+	if err != nil {
+		fmt.Printf("Erro ao executar consulta: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	type unnumbered struct {
+		suggestion JerseySuggestion
+		overall    int
+	}
+	var players []unnumbered
+	for rows.Next() {
+		var u unnumbered
+		if err := rows.Scan(&u.suggestion.PlayerID, &u.suggestion.PlayerName, &u.suggestion.Position, &u.overall); err != nil {
+			return nil, err
+		}
+		players = append(players, u)
+	}
+
+	// Jogadores de maior overall escolhem primeiro
+	sort.SliceStable(players, func(i, j int) bool { return players[i].overall > players[j].overall })
+
+	var suggestions []JerseySuggestion
+	for _, u := range players {
+		s := u.suggestion
+		s.Suggestions = suggestJerseyNumbers(s.Position, taken, retiredSet, 3)
+		if len(s.Suggestions) > 0 {
+			first := s.Suggestions[0]
+			taken[first] = append(taken[first], models.JerseyAssignment{PlayerID: s.PlayerID, Position: s.Position, Number: first})
+		}
+		suggestions = append(suggestions, s)
+	}
+	return suggestions, nil
+}
+
+// AddRetiredNumber aposenta um número do time
+func AddRetiredNumber(retired models.RetiredNumber) error {
+	if retired.Number < 0 || retired.Number > 99 {
+		return fmt.Errorf("número inválido: %d (use 0 a 99)", retired.Number)
+	}
+	_, err := database.DB.Exec(`
+        INSERT INTO retired_numbers (team_id, number, person_id, player_name, year_retired)
+        VALUES (?, ?, ?, ?, ?)
+    `, retired.TeamID, retired.Number, retired.PersonID, retired.PlayerName, retired.YearRetired)
+	if err != nil {
+		fmt.Printf("Erro ao aposentar número: %v\n", err)
+		return err
+	}
+	return nil
+}
+
+// GetRetiredNumbers lista os números aposentados do time
+func GetRetiredNumbers(teamID int) ([]models.RetiredNumber, error) {
+	rows, err := database.DB.Query(`
+        SELECT team_id, number, person_id, player_name, year_retired
+        FROM retired_numbers WHERE team_id = ? ORDER BY number
+    `, teamID)
+	if err != nil {
+		fmt.Printf("Erro ao executar consulta: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var numbers []models.RetiredNumber
+	for rows.Next() {
+		var r models.RetiredNumber
+		if err := rows.Scan(&r.TeamID, &r.Number, &r.PersonID, &r.PlayerName, &r.YearRetired); err != nil {
+			fmt.Printf("Erro ao escanear resultados: %v\n", err)
+			return nil, err
+		}
+		numbers = append(numbers, r)
+	}
+	return numbers, nil
+}
+
+// DeleteRetiredNumber devolve um número aposentado ao uso
+func DeleteRetiredNumber(teamID int, number int) error {
+	_, err := database.DB.Exec("DELETE FROM retired_numbers WHERE team_id = ? AND number = ?", teamID, number)
+	return err
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+)
+
+// ErrInvalidSort indica um campo de ordenação não aceito pela listagem
+var ErrInvalidSort = errors.New("ordenação inválida")
+
+// ListOptions define página e ordenação das listagens
+type ListOptions struct {
+	Page       int    // Começa em 1
+	PageSize   int    // 0 = tamanho padrão
+	SortBy     string // Nome público do campo (ex: "stars"); vazio = ordenação padrão da listagem
+	Descending bool
+}
+
+// PageInfo acompanha a resposta das listagens paginadas
+type PageInfo struct {
+	Page       int `json:"page"`
+	PageSize   int `json:"page_size"`
+	Total      int `json:"total"`
+	TotalPages int `json:"total_pages"`
+}
+
+// normalize aplica os limites de página e tamanho
+func (o ListOptions) normalize() ListOptions {
+	if o.Page < 1 {
+		o.Page = 1
+	}
+	if o.PageSize <= 0 {
+		o.PageSize = defaultPageSize
+	}
+	if o.PageSize > maxPageSize {
+		o.PageSize = maxPageSize
+	}
+	return o
+}
+
+// orderAndLimit monta ORDER BY e LIMIT a partir das opções. sortColumns mapeia os nomes aceitos
+// em SortBy para colunas SQL, o que impede ordenar por expressões arbitrárias
+func (o ListOptions) orderAndLimit(sortColumns map[string]string, defaultOrder string, tieBreaker string) (string, []interface{}, error) {
+	order := defaultOrder
+	if o.SortBy != "" {
+		column, ok := sortColumns[o.SortBy]
+		if !ok {
+			var accepted []string
+			for name := range sortColumns {
+				accepted = append(accepted, name)
+			}
+			sort.Strings(accepted)
+			return "", nil, fmt.Errorf("%w: %s (use %s)", ErrInvalidSort, o.SortBy, strings.Join(accepted, ", "))
+		}
+		direction := "ASC"
+		if o.Descending {
+			direction = "DESC"
+		}
+		order = column + " " + direction
+	}
+	return fmt.Sprintf(" ORDER BY %s, %s LIMIT ? OFFSET ?", order, tieBreaker),
+		[]interface{}{o.PageSize, (o.Page - 1) * o.PageSize}, nil
+}
+
+func (o ListOptions) pageInfo(total int) PageInfo {
+	return PageInfo{Page: o.Page, PageSize: o.PageSize, Total: total, TotalPages: (total + o.PageSize - 1) / o.PageSize}
+}
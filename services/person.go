@@ -0,0 +1,319 @@
+package services
+
+import (
+	"database/sql"
+	"dynastyTracker/database"
+	"dynastyTracker/models"
+	"errors"
+	"fmt"
+)
+
+// createPerson cria uma nova identidade e devolve o person_id
+func createPerson(exec sqlExecer, name string) (int, error) {
+	result, err := exec.Exec("INSERT INTO people (name) VALUES (?)", name)
+	if err != nil {
+		fmt.Printf("Erro ao criar pessoa: %v\n", err)
+		return 0, err
+	}
+	id, err := result.LastInsertId()
+	return int(id), err
+}
+
+// ErrPersonNotFound indica uma pessoa informada que não existe
+var ErrPersonNotFound = errors.New("pessoa não encontrada")
+
+// resolvePerson usa a pessoa informada (validando que existe) ou cria uma nova com o nome.
+// Dentro de uma transação, recebe a própria tx para enxergar pessoas criadas nela
+func resolvePerson(exec sqlRunner, personID *int, name string) (int, error) {
+	if personID == nil {
+		return createPerson(exec, name)
+	}
+	var exists int
+	err := exec.QueryRow("SELECT person_id FROM people WHERE person_id = ?", *personID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("%w: %d", ErrPersonNotFound, *personID)
+	}
+	return exists, err
+}
+
+// GetPerson reúne tudo o que está vinculado à pessoa: recrutamento, passagens pelo elenco,
+// saídas e recordes históricos
+func GetPerson(personID int) (models.Person, error) {
+	person := models.Person{PersonID: personID}
+	err := database.DB.QueryRow("SELECT name FROM people WHERE person_id = ?", personID).Scan(&person.Name)
+	if err != nil {
+		return person, err
+	}
+
+	recruitRows, err := database.DB.Query("SELECT recruit_id FROM recruits WHERE person_id = ? ORDER BY recruitment_year", personID)
+	if err != nil {
+		fmt.Printf("Erro ao executar consulta: %v\n", err)
+		return person, err
+	}
+	var recruitIDs []int
+	for recruitRows.Next() {
+		var id int
+		if err := recruitRows.Scan(&id); err != nil {
+			recruitRows.Close()
+			return person, err
+		}
+		recruitIDs = append(recruitIDs, id)
+	}
+	recruitRows.Close()
+	for _, id := range recruitIDs {
+		recruit, err := GetRecruit(id)
+		if err != nil {
+			return person, err
+		}
+		person.Recruits = append(person.Recruits, recruit)
+	}
+
+	playerRows, err := database.DB.Query("SELECT "+playerColumns+" FROM players WHERE person_id = ? ORDER BY player_id", personID)
+	if err != nil {
+		fmt.Printf("Erro ao executar consulta: %v\n", err)
+		return person, err
+	}
+	defer playerRows.Close()
+	for playerRows.Next() {
+		player, err := scanPlayer(playerRows)
+		if err != nil {
+			return person, err
+		}
+		person.Players = append(person.Players, player)
+	}
+
+	departureRows, err := database.DB.Query(`SELECT `+departureColumns+`
+        FROM departures d
+        JOIN players p ON p.player_id = d.player_id
+        WHERE p.person_id = ?
+        ORDER BY d.year`, personID)
+	if err != nil {
+		fmt.Printf("Erro ao executar consulta: %v\n", err)
+		return person, err
+	}
+	defer departureRows.Close()
+	for departureRows.Next() {
+		departure, err := scanDeparture(departureRows)
+		if err != nil {
+			return person, err
+		}
+		person.Departures = append(person.Departures, departure)
+	}
+
+	recordRows, err := database.DB.Query("SELECT "+historicalColumns+" FROM historicalrecords WHERE person_id = ? ORDER BY year_start", personID)
+	if err != nil {
+		fmt.Printf("Erro ao executar consulta: %v\n", err)
+		return person, err
+	}
+	defer recordRows.Close()
+	for recordRows.Next() {
+		record, err := scanHistoricalRecord(recordRows)
+		if err != nil {
+			return person, err
+		}
+		person.HistoricalRecords = append(person.HistoricalRecords, record)
+	}
+	return person, nil
+}
+
+// MergePeople une duas identidades que são o mesmo atleta: tudo o que estava em sourceID
+// passa para targetID, e sourceID é excluída
+func MergePeople(targetID int, sourceID int) error {
+	if targetID == sourceID {
+		return fmt.Errorf("não é possível unir uma pessoa com ela mesma")
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, id := range []int{targetID, sourceID} {
+		var exists int
+		err := tx.QueryRow("SELECT person_id FROM people WHERE person_id = ? FOR UPDATE", id).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("pessoa não encontrada: %d", id)
+		}
+	}
+
+	for _, table := range []string{"recruits", "players", "historicalrecords"} {
+		_, err = tx.Exec(fmt.Sprintf("UPDATE %s SET person_id = ? WHERE person_id = ?", table), targetID, sourceID)
+		if err != nil {
+			fmt.Printf("Erro ao unir pessoas em %s: %v\n", table, err)
+			return err
+		}
+	}
+	if _, err = tx.Exec("DELETE FROM people WHERE person_id = ?", sourceID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// SplitPerson separa registros vinculados por engano à mesma pessoa, criando uma nova
+// identidade para eles. Devolve o person_id criado
+func SplitPerson(personID int, split models.PersonSplit) (int, error) {
+	if len(split.RecruitIDs)+len(split.PlayerIDs)+len(split.RecordIDs) == 0 {
+		return 0, fmt.Errorf("informe os registros que formam a nova pessoa")
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var name string
+	err = tx.QueryRow("SELECT name FROM people WHERE person_id = ? FOR UPDATE", personID).Scan(&name)
+	if err != nil {
+		return 0, fmt.Errorf("pessoa não encontrada: %d", personID)
+	}
+	if split.Name != "" {
+		name = split.Name
+	}
+
+	newID, err := createPerson(tx, name)
+	if err != nil {
+		return 0, err
+	}
+
+	move := func(table string, idColumn string, ids []int) error {
+		for _, id := range ids {
+			result, err := tx.Exec(fmt.Sprintf("UPDATE %s SET person_id = ? WHERE %s = ? AND person_id = ?", table, idColumn),
+				newID, id, personID)
+			if err != nil {
+				return err
+			}
+			if affected, _ := result.RowsAffected(); affected == 0 {
+				return fmt.Errorf("%s %d não pertence à pessoa %d", idColumn, id, personID)
+			}
+		}
+		return nil
+	}
+	if err := move("recruits", "recruit_id", split.RecruitIDs); err != nil {
+		return 0, err
+	}
+	if err := move("players", "player_id", split.PlayerIDs); err != nil {
+		return 0, err
+	}
+	if err := move("historicalrecords", "record_id", split.RecordIDs); err != nil {
+		return 0, err
+	}
+
+	return newID, tx.Commit()
+}
+
+type PeopleBackfillReport struct {
+	PeopleCreated       int      `json:"people_created"`
+	PlayersFromRecruits int      `json:"players_from_recruits"` // Jogadores que herdaram a pessoa do recruta de origem
+	RecordsLinked       int      `json:"records_linked"`        // Recordes ligados a um único jogador com o mesmo nome
+	RecordsUnmatched    []string `json:"records_unmatched"`     // Nome ambíguo ou sem jogador: revisar com MergePeople
+}
+
+// BackfillPeople cria identidades para os registros anteriores ao person_id. O nome só é usado
+// aqui, uma única vez, para ligar recordes históricos a jogadores; nomes repetidos ficam sem
+// vínculo para revisão manual
+func BackfillPeople() (PeopleBackfillReport, error) {
+	var report PeopleBackfillReport
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return report, err
+	}
+	defer tx.Rollback()
+
+	type pending struct {
+		id   int
+		name string
+	}
+	collect := func(query string) ([]pending, error) {
+		rows, err := tx.Query(query)
+		if err != nil {
+			fmt.Printf("Erro ao executar consulta: %v\n", err)
+			return nil, err
+		}
+		defer rows.Close()
+		var items []pending
+		for rows.Next() {
+			var item pending
+			if err := rows.Scan(&item.id, &item.name); err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, rows.Err()
+	}
+
+	recruits, err := collect("SELECT recruit_id, player_name FROM recruits WHERE person_id IS NULL")
+	if err != nil {
+		return report, err
+	}
+	for _, recruit := range recruits {
+		personID, err := createPerson(tx, recruit.name)
+		if err != nil {
+			return report, err
+		}
+		if _, err := tx.Exec("UPDATE recruits SET person_id = ? WHERE recruit_id = ?", personID, recruit.id); err != nil {
+			return report, err
+		}
+		report.PeopleCreated++
+	}
+
+	result, err := tx.Exec(`
+        UPDATE players p JOIN recruits r ON r.recruit_id = p.recruit_id
+        SET p.person_id = r.person_id
+        WHERE p.person_id IS NULL
+    `)
+	if err != nil {
+		fmt.Printf("Erro ao vincular jogadores aos recrutas: %v\n", err)
+		return report, err
+	}
+	affected, _ := result.RowsAffected()
+	report.PlayersFromRecruits = int(affected)
+
+	players, err := collect("SELECT player_id, name FROM players WHERE person_id IS NULL")
+	if err != nil {
+		return report, err
+	}
+	for _, player := range players {
+		personID, err := createPerson(tx, player.name)
+		if err != nil {
+			return report, err
+		}
+		if _, err := tx.Exec("UPDATE players SET person_id = ? WHERE player_id = ?", personID, player.id); err != nil {
+			return report, err
+		}
+		report.PeopleCreated++
+	}
+
+	records, err := collect("SELECT record_id, player_name FROM historicalrecords WHERE person_id IS NULL")
+	if err != nil {
+		return report, err
+	}
+	for _, record := range records {
+		var matches int
+		var personID sql.NullInt64
+		err := tx.QueryRow("SELECT COUNT(DISTINCT person_id), MIN(person_id) FROM players WHERE name = ?", record.name).
+			Scan(&matches, &personID)
+		if err != nil {
+			return report, err
+		}
+
+		if matches == 1 {
+			report.RecordsLinked++
+		} else {
+			report.RecordsUnmatched = append(report.RecordsUnmatched, record.name)
+			id, err := createPerson(tx, record.name)
+			if err != nil {
+				return report, err
+			}
+			personID = sql.NullInt64{Int64: int64(id), Valid: true}
+			report.PeopleCreated++
+		}
+		if _, err := tx.Exec("UPDATE historicalrecords SET person_id = ? WHERE record_id = ?", personID.Int64, record.id); err != nil {
+			return report, err
+		}
+	}
+
+	return report, tx.Commit()
+}
@@ -4,14 +4,16 @@ import (
 	"database/sql"
 	"dynastyTracker/database"
 	"dynastyTracker/models"
+	"errors"
 	"fmt"
+	"sort"
 )
 
 // GetPlayers retorna a lista de todos os jogadores
 func GetPlayers() ([]models.Player, error) {
 	var players []models.Player
 
-	query := "SELECT player_id, name, position, overall, class_year, team_id FROM players"
+	query := "SELECT player_id, name, position, overall, class_year, team_id, redshirted, eligibility_exhausted FROM players WHERE active = 1"
 	rows, err := database.DB.Query(query)
 	if err != nil {
 		fmt.Println("Erro ao executar a consulta SQL:", err) // Log do erro SQL
@@ -21,7 +23,8 @@ func GetPlayers() ([]models.Player, error) {
 
 	for rows.Next() {
 		var player models.Player
-		if err := rows.Scan(&player.PlayerID, &player.Name, &player.Position, &player.Overall, &player.ClassYear, &player.TeamID); err != nil {
+		if err := rows.Scan(&player.PlayerID, &player.Name, &player.Position, &player.Overall, &player.ClassYear, &player.TeamID,
+			&player.Redshirted, &player.EligibilityExhausted); err != nil {
 			fmt.Println("Erro ao escanear dados do jogador:", err) // Log de erro de escaneamento
 			return nil, err
 		}
@@ -50,138 +53,549 @@ func AddPlayer(player models.Player) error {
 	// Atualizar o player com o team_id encontrado
 	player.TeamID = teamID
 
-	// Verificar o limite do elenco
-	var playerCount int
-	err = database.DB.QueryRow("SELECT COUNT(*) FROM players WHERE team_id = ?", player.TeamID).Scan(&playerCount)
+	// Normalizar o ano acadêmico para o formato padrão (FR, RS FR, SO...)
+	player.ClassYear, err = ParseClassYear(string(player.ClassYear))
 	if err != nil {
-		fmt.Printf("Erro ao contar jogadores: %v\n", err)
 		return err
 	}
 
-	if playerCount >= 85 {
-		return fmt.Errorf("O elenco atingiu o limite máximo de 85 jogadores")
-	}
-
-	// Inserir o jogador se o limite não foi atingido
-	query := `
-        INSERT INTO players (name, position, overall, games_played, games_started, snaps_played, class_year, team_id, recruitment_source)
-        VALUES (?, ?, ?, 0, 0, 0, ?, ?, ?);
-    `
-	_, err = database.DB.Exec(query, player.Name, player.Position, player.Overall, player.ClassYear, player.TeamID, player.RecruitmentSource)
+	err = ValidateAttributes(player.Position, player.Archetype, player.Attributes)
 	if err != nil {
-		fmt.Printf("Erro ao adicionar jogador: %v\n", err)
 		return err
 	}
-	return nil
+
+	if player.DevTrait != "" {
+		player.DevTrait, err = NormalizeDevTrait(player.DevTrait)
+		if err != nil {
+			return err
+		}
+	}
+
+	// A inserção, os atributos e a verificação das regras de elenco acontecem na mesma transação
+	err = withRosterRules([]int{player.TeamID}, func(tx *sql.Tx) error {
+		personID, err := resolvePerson(tx, player.PersonID, player.Name)
+		if err != nil {
+			return err
+		}
+		result, err := tx.Exec(`
+            INSERT INTO players (name, position, overall, games_played, games_started, snaps_played, class_year, team_id, recruitment_source,
+                redshirted, redshirt_year, eligibility_exhausted, archetype, dev_trait, dev_trait_revealed_year, active, walk_on, person_id,
+                height, weight, hometown, home_state, tendency)
+            VALUES (?, ?, ?, 0, 0, 0, ?, ?, ?, ?, ?, 0, ?, NULLIF(?, ''), ?, 1, ?, ?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), NULLIF(?, ''));
+        `, player.Name, player.Position, player.Overall, player.ClassYear, player.TeamID, player.RecruitmentSource,
+			player.Redshirted, player.RedshirtYear, player.Archetype, player.DevTrait, player.DevTraitRevealedYear, player.WalkOn, personID,
+			player.Height, player.Weight, player.Hometown, player.HomeState, player.Tendency)
+		if err != nil {
+			fmt.Printf("Erro ao adicionar jogador: %v\n", err)
+			return err
+		}
+		playerID, err := result.LastInsertId()
+		if err != nil {
+			return err
+		}
+		return replaceAttributes(tx, "player_attributes", "player_id", int(playerID), player.Attributes)
+	})
+	return err
 }
 
-// GetPlayer obtém um jogador específico pelo ID
-func GetPlayer(id int) (models.Player, error) {
+// playerColumns lista as colunas de players na ordem esperada por scanPlayer
+const playerColumns = `player_id, name, position, overall, games_played, games_started, snaps_played, class_year, team_id,
+        recruitment_source, redshirted, redshirt_year, eligibility_exhausted, archetype, COALESCE(dev_trait, ''),
+        dev_trait_revealed_year, active, recruit_id, walk_on, person_id, COALESCE(height, 0), COALESCE(weight, 0),
+        COALESCE(hometown, ''), COALESCE(home_state, ''), COALESCE(tendency, '')`
+
+// rowScanner é implementado tanto por *sql.Row quanto por *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// sqlExecer é implementado tanto por *sql.DB quanto por *sql.Tx
+type sqlExecer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// sqlRunner é implementado tanto por *sql.DB quanto por *sql.Tx, para funções que leem e
+// gravam e precisam enxergar o que a transação do chamador já gravou
+type sqlRunner interface {
+	sqlExecer
+	sqlQueryer
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// scanPlayer lê uma linha selecionada com playerColumns
+func scanPlayer(row rowScanner) (models.Player, error) {
 	var player models.Player
-	row := database.DB.QueryRow("SELECT * FROM players WHERE player_id = ?", id)
 	err := row.Scan(&player.PlayerID, &player.Name, &player.Position, &player.Overall,
-		&player.GamesPlayed, &player.GamesStarted, &player.SnapsPlayed, &player.ClassYear, &player.TeamID)
+		&player.GamesPlayed, &player.GamesStarted, &player.SnapsPlayed, &player.ClassYear, &player.TeamID,
+		&player.RecruitmentSource, &player.Redshirted, &player.RedshirtYear, &player.EligibilityExhausted, &player.Archetype, &player.DevTrait,
+		&player.DevTraitRevealedYear, &player.Active, &player.RecruitID, &player.WalkOn, &player.PersonID,
+		&player.Height, &player.Weight, &player.Hometown, &player.HomeState, &player.Tendency)
+	return player, err
+}
+
+// GetPlayer obtém um jogador específico pelo ID
+func GetPlayer(id int) (models.Player, error) {
+	row := database.DB.QueryRow("SELECT "+playerColumns+" FROM players WHERE player_id = ?", id)
+	player, err := scanPlayer(row)
 	if err == sql.ErrNoRows {
 		return player, err
 	}
+
+	attributes, err := loadAttributes("player_attributes", "player_id", []int{player.PlayerID})
+	if err != nil {
+		return player, err
+	}
+	player.Attributes = attributes[player.PlayerID]
+
+	if player.RecruitID != nil {
+		profile, err := getRecruitingProfile(*player.RecruitID)
+		if err != nil && err != sql.ErrNoRows {
+			return player, err
+		}
+		if err == nil {
+			player.Recruiting = &profile
+		}
+	}
 	return player, nil
 }
 
-// DeletePlayer exclui um jogador pelo ID
+// getRecruitingProfile lê os dados de recrutamento que acompanham o jogador promovido
+func getRecruitingProfile(recruitID int) (models.RecruitingProfile, error) {
+	var profile models.RecruitingProfile
+	err := database.DB.QueryRow(`
+        SELECT recruit_id, stars, national_rank, position_rank, COALESCE(gem_bust, ''), overall,
+            COALESCE(dev_trait, ''), recruitment_source, recruitment_year, eligibility_remaining
+        FROM recruits WHERE recruit_id = ?
+    `, recruitID).Scan(&profile.RecruitID, &profile.Stars, &profile.NationalRank, &profile.PositionRank, &profile.GemBust,
+		&profile.SigningOverall, &profile.SigningDevTrait, &profile.RecruitmentSource, &profile.RecruitmentYear,
+		&profile.EligibilityRemaining)
+	if err != nil {
+		return profile, err
+	}
+
+	// Chegadas pelo portal trazem as estatísticas da escola anterior
+	var transferID int
+	err = database.DB.QueryRow("SELECT transfer_id FROM portal_transfers WHERE recruit_id = ?", recruitID).Scan(&transferID)
+	if err == sql.ErrNoRows {
+		return profile, nil
+	}
+	if err != nil {
+		return profile, err
+	}
+	profile.PriorStats, err = getTransferPriorStats(transferID)
+	return profile, err
+}
+
+// DeletePlayer exclui um jogador pelo ID. Serve apenas para corrigir cadastros errados;
+// saídas do elenco devem usar AddDeparture, que preserva o histórico
 func DeletePlayer(id int) error {
 	_, err := database.DB.Exec("DELETE FROM players WHERE player_id = ?", id)
 	return err
 }
 
+// UpdatePlayer grava todos os campos editáveis do jogador (archetype, redshirt, walk-on etc.).
+// Quem chama deve partir do cadastro atual (GetPlayer) para não zerar campos omitidos
 func UpdatePlayer(player models.Player) error {
-	// Buscar o team_id com base no nome do time
-	teamID, err := getTeamIDByName(player.TeamName)
+	var err error
+	// Buscar o team_id com base no nome do time; sem nome, o jogador continua no time atual
+	if player.TeamName != "" {
+		teamID, err := getTeamIDByName(player.TeamName)
+		if err != nil {
+			fmt.Printf("Erro ao buscar o team_id: %v\n", err)
+			return err
+		}
+
+		// Atualizar o player com o novo team_id
+		player.TeamID = teamID
+	}
+
+	player.ClassYear, err = ParseClassYear(string(player.ClassYear))
 	if err != nil {
-		fmt.Printf("Erro ao buscar o team_id: %v\n", err)
 		return err
 	}
 
-	// Atualizar o player com o novo team_id
-	player.TeamID = teamID
+	err = ValidateAttributes(player.Position, player.Archetype, player.Attributes)
+	if err != nil {
+		return err
+	}
+
+	// Mudanças de posição, ano acadêmico, bolsa ou time passam pelas regras dos dois times envolvidos
+	var previousTeamID int
+	err = database.DB.QueryRow("SELECT team_id FROM players WHERE player_id = ?", player.PlayerID).Scan(&previousTeamID)
+	if err != nil {
+		return err
+	}
 
-	_, err = database.DB.Exec(`UPDATE players SET name=?, position=?, overall=?, games_played=?, games_started=?, 
-        snaps_played=?, class_year=?, team_id=? WHERE player_id=?`,
-		player.Name, player.Position, player.Overall, player.GamesPlayed, player.GamesStarted,
-		player.SnapsPlayed, player.ClassYear, player.TeamID, player.PlayerID)
+	// games_started é derivado das escalações (RecalculateGamesStarted) e não é editado aqui
+	err = withRosterRules([]int{previousTeamID, player.TeamID}, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`UPDATE players SET name=?, position=?, overall=?, games_played=?, 
+            snaps_played=?, class_year=?, team_id=?, redshirted=?, redshirt_year=?, eligibility_exhausted=?, archetype=?, walk_on=?,
+            height=COALESCE(NULLIF(?, 0), height), weight=COALESCE(NULLIF(?, 0), weight), hometown=COALESCE(NULLIF(?, ''), hometown),
+            home_state=COALESCE(NULLIF(?, ''), home_state), tendency=COALESCE(NULLIF(?, ''), tendency) WHERE player_id=?`,
+			player.Name, player.Position, player.Overall, player.GamesPlayed,
+			player.SnapsPlayed, player.ClassYear, player.TeamID, player.Redshirted, player.RedshirtYear,
+			player.EligibilityExhausted, player.Archetype, player.WalkOn,
+			player.Height, player.Weight, player.Hometown, player.HomeState, player.Tendency, player.PlayerID)
+		if err != nil {
+			return err
+		}
+
+		// Atributos só são substituídos quando enviados na requisição
+		if player.Attributes != nil {
+			return replaceAttributes(tx, "player_attributes", "player_id", player.PlayerID, player.Attributes)
+		}
+		return nil
+	})
 	return err
 }
 
-func GetPlayersWithFilters(position string, teamID int) ([]models.Player, error) {
-	query := "SELECT * FROM players WHERE 1=1"
+type PlayerPage struct {
+	Players []models.Player `json:"players"`
+	PageInfo
+}
+
+// Campos aceitos na ordenação da listagem de jogadores
+var playerSortColumns = map[string]string{
+	"name":          "name",
+	"position":      "position",
+	"overall":       "overall",
+	"class_year":    "class_year",
+	"games_played":  "games_played",
+	"games_started": "games_started",
+}
+
+// GetPlayersWithFilters busca jogadores por posição, time, arquétipo e faixas de atributos
+// (ex: WR com speed >= 92), paginados e ordenados
+func GetPlayersWithFilters(position string, teamID int, archetype string, attributeFilters []AttributeFilter, options ListOptions) (PlayerPage, error) {
+	options = options.normalize()
+	page := PlayerPage{Players: []models.Player{}}
+
+	where := " WHERE 1=1"
 	var args []interface{}
 
 	if position != "" {
-		query += " AND position = ?"
+		where += " AND position = ?"
 		args = append(args, position)
 	}
 	if teamID > 0 {
-		query += " AND team_id = ?"
+		where += " AND team_id = ?"
 		args = append(args, teamID)
 	}
+	if archetype != "" {
+		where += " AND archetype = ?"
+		args = append(args, archetype)
+	}
+	for _, filter := range attributeFilters {
+		where += " AND EXISTS (SELECT 1 FROM player_attributes a WHERE a.player_id = players.player_id AND a.attribute = ?"
+		args = append(args, filter.Attribute)
+		if filter.Min > 0 {
+			where += " AND a.value >= ?"
+			args = append(args, filter.Min)
+		}
+		if filter.Max > 0 {
+			where += " AND a.value <= ?"
+			args = append(args, filter.Max)
+		}
+		where += ")"
+	}
 
-	rows, err := database.DB.Query(query, args...)
+	suffix, pageArgs, err := options.orderAndLimit(playerSortColumns, "name", "player_id")
 	if err != nil {
-		return nil, err
+		return page, err
+	}
+
+	var total int
+	if err := database.DB.QueryRow("SELECT COUNT(*) FROM players"+where, args...).Scan(&total); err != nil {
+		fmt.Printf("Erro ao contar jogadores: %v\n", err)
+		return page, err
+	}
+	page.PageInfo = options.pageInfo(total)
+
+	rows, err := database.DB.Query("SELECT "+playerColumns+" FROM players"+where+suffix, append(args, pageArgs...)...)
+	if err != nil {
+		return page, err
 	}
 	defer rows.Close()
 
-	var players []models.Player
 	for rows.Next() {
-		var player models.Player
-		err := rows.Scan(&player.PlayerID, &player.Name, &player.Position, &player.Overall, &player.GamesPlayed,
-			&player.GamesStarted, &player.SnapsPlayed, &player.ClassYear, &player.TeamID)
+		player, err := scanPlayer(rows)
 		if err != nil {
-			return nil, err
+			return page, err
 		}
-		players = append(players, player)
+		page.Players = append(page.Players, player)
 	}
 
-	return players, nil
+	playerIDs := make([]int, len(page.Players))
+	for i, player := range page.Players {
+		playerIDs[i] = player.PlayerID
+	}
+	attributes, err := loadAttributes("player_attributes", "player_id", playerIDs)
+	if err != nil {
+		return page, err
+	}
+	for i := range page.Players {
+		page.Players[i].Attributes = attributes[page.Players[i].PlayerID]
+	}
+
+	return page, nil
 }
 
-func PromoteRecruits(currentYear int) error {
+type PromotedRecruit struct {
+	RecruitID int    `json:"recruit_id"`
+	PlayerID  int    `json:"player_id"`
+	Name      string `json:"name"`
+	Position  string `json:"position"`
+}
+
+type SkippedRecruit struct {
+	RecruitID  int      `json:"recruit_id"`
+	Name       string   `json:"name"`
+	Position   string   `json:"position"`
+	Reason     string   `json:"reason"`
+	PlayerID   *int     `json:"player_id,omitempty"` // Jogador do elenco que causou o conflito, se houver
+	Violations []string `json:"violations,omitempty"`
+}
+
+// PromotionReport resume a promoção dos recrutas: novos jogadores, recrutas vinculados a
+// jogadores que já estavam no elenco e recrutas deixados de fora
+type PromotionReport struct {
+	Year     int               `json:"year"`
+	Promoted []PromotedRecruit `json:"promoted"`
+	Linked   []PromotedRecruit `json:"linked"`
+	Skipped  []SkippedRecruit  `json:"skipped"`
+
+	JerseySuggestions []JerseySuggestion `json:"jersey_suggestions"` // Números sugeridos para os promovidos
+}
+
+// findRosterDuplicate procura no elenco do time um jogador ativo que possa ser o mesmo recruta:
+// mesma pessoa ou mesmo nome e posição (cadastrado à mão antes da promoção). Só a mesma pessoa é
+// vinculada automaticamente; nome e posição iguais ficam para conferência manual
+func findRosterDuplicate(q sqlRunner, recruit models.Recruit) (int, *int, *int, bool, error) {
+	var playerID int
+	var recruitID, personID *int
+	err := q.QueryRow(`
+        SELECT player_id, recruit_id, person_id FROM players
+        WHERE team_id = ? AND active = 1
+          AND ((person_id IS NOT NULL AND person_id = ?) OR (LOWER(name) = LOWER(?) AND position = ?))
+        ORDER BY player_id
+        LIMIT 1
+    `, recruit.TeamID, recruit.PersonID, recruit.PlayerName, recruit.Position).Scan(&playerID, &recruitID, &personID)
+	if err == sql.ErrNoRows {
+		return 0, nil, nil, false, nil
+	}
+	if err != nil {
+		return 0, nil, nil, false, err
+	}
+	return playerID, recruitID, personID, true, nil
+}
+
+// PromoteRecruits transforma os recrutas do ano anterior em jogadores. Os recrutas
+// permanecem em recruits, vinculados ao jogador por players.recruit_id, e o jogador
+// herda o perfil do recrutamento (altura, peso, cidade natal, tendência, dev trait)
+func PromoteRecruits(currentYear int) (PromotionReport, error) {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return PromotionReport{Year: currentYear}, err
+	}
+	defer tx.Rollback()
+
+	report, promotedTeams, err := promoteRecruits(tx, currentYear)
+	if err != nil {
+		return report, err
+	}
+	if err := tx.Commit(); err != nil {
+		return report, err
+	}
+	// A promoção já foi gravada; sem sugestões, os números são escolhidos à mão
+	if err := addJerseySuggestions(&report, promotedTeams); err != nil {
+		fmt.Printf("Erro ao sugerir números: %v\n", err)
+	}
+	return report, nil
+}
+
+// promoteRecruits promove os recrutas dentro da transação do chamador, de modo que a promoção
+// seja desfeita junto com o resto em caso de erro. Recrutas que violariam as regras de elenco são
+// pulados sem afetar a transação. Devolve também os jogadores promovidos por time
+func promoteRecruits(tx *sql.Tx, currentYear int) (PromotionReport, map[int][]int, error) {
 	recruitmentYear := currentYear - 1
+	report := PromotionReport{Year: currentYear, Promoted: []PromotedRecruit{}, Linked: []PromotedRecruit{}, Skipped: []SkippedRecruit{},
+		JerseySuggestions: []JerseySuggestion{}}
+	promotedTeams := make(map[int][]int)
 
-	rows, err := database.DB.Query(`
-        SELECT recruit_id, player_name, position, overall, class, team_id, recruitment_source
-        FROM recruits
+	// Recrutas já promovidos (com jogador vinculado) são ignorados
+	rows, err := tx.Query(`
+        SELECT recruit_id, player_name, position, overall, class, team_id, recruitment_source, archetype,
+            COALESCE(dev_trait, ''), person_id, COALESCE(height, 0), COALESCE(weight, 0),
+            COALESCE(hometown, ''), COALESCE(home_state, ''), COALESCE(tendency, '')
+        FROM recruits r
         WHERE recruitment_year = ?
+          AND NOT EXISTS (SELECT 1 FROM players p WHERE p.recruit_id = r.recruit_id)
+        ORDER BY team_id, national_rank = 0, national_rank, recruit_id
     `, recruitmentYear)
 	if err != nil {
 		fmt.Printf("Erro ao buscar recrutas: %v\n", err)
-		return err
+		return report, nil, err
 	}
-	defer rows.Close()
-
+	// Os recrutas são lidos antes das promoções, que usam a mesma transação
+	var recruits []models.Recruit
 	for rows.Next() {
 		var recruit models.Recruit
-		err := rows.Scan(&recruit.RecruitID, &recruit.PlayerName, &recruit.Position, &recruit.Overall, &recruit.Class, &recruit.TeamID, &recruit.RecruitmentSource)
+		err := rows.Scan(&recruit.RecruitID, &recruit.PlayerName, &recruit.Position, &recruit.Overall, &recruit.Class, &recruit.TeamID,
+			&recruit.RecruitmentSource, &recruit.Archetype, &recruit.DevTrait, &recruit.PersonID, &recruit.Height, &recruit.Weight,
+			&recruit.Hometown, &recruit.HomeState, &recruit.Tendency)
 		if err != nil {
+			rows.Close()
 			fmt.Printf("Erro ao escanear recruta: %v\n", err)
-			return err
+			return report, nil, err
 		}
+		recruits = append(recruits, recruit)
+	}
+	rows.Close()
 
-		_, err = database.DB.Exec(`
-            INSERT INTO players (name, position, overall, games_played, games_started, snaps_played, class_year, team_id, recruitment_source)
-            VALUES (?, ?, ?, 0, 0, 0, ?, ?, ?);
-        `, recruit.PlayerName, recruit.Position, recruit.Overall, recruit.Class, recruit.TeamID, recruit.RecruitmentSource)
+	for _, recruit := range recruits {
+		skip := SkippedRecruit{RecruitID: recruit.RecruitID, Name: recruit.PlayerName, Position: recruit.Position}
+
+		// Jogador já no elenco: vincula ao recruta em vez de criar um duplicado
+		existingID, existingRecruit, existingPerson, found, err := findRosterDuplicate(tx, recruit)
+		if err != nil {
+			return report, nil, err
+		}
+		if found {
+			skip.PlayerID = &existingID
+			switch {
+			case existingRecruit != nil:
+				skip.Reason = fmt.Sprintf("jogador %d já está vinculado ao recruta %d", existingID, *existingRecruit)
+				report.Skipped = append(report.Skipped, skip)
+			case existingPerson == nil || recruit.PersonID == nil || *existingPerson != *recruit.PersonID:
+				skip.Reason = fmt.Sprintf("jogador %d tem o mesmo nome e posição mas não é a mesma pessoa; confira e unifique em /api/people/merge se forem a mesma", existingID)
+				report.Skipped = append(report.Skipped, skip)
+			default:
+				// Só preenche o que o jogador ainda não tem
+				_, err := tx.Exec(`
+                    UPDATE players SET recruit_id = ?,
+                        height = COALESCE(NULLIF(height, 0), NULLIF(?, 0)), weight = COALESCE(NULLIF(weight, 0), NULLIF(?, 0)),
+                        hometown = COALESCE(hometown, NULLIF(?, '')), home_state = COALESCE(home_state, NULLIF(?, '')),
+                        tendency = COALESCE(tendency, NULLIF(?, ''))
+                    WHERE player_id = ?
+                `, recruit.RecruitID, recruit.Height, recruit.Weight, recruit.Hometown, recruit.HomeState,
+					recruit.Tendency, existingID)
+				if err != nil {
+					fmt.Printf("Erro ao vincular recruta ao jogador: %v\n", err)
+					return report, nil, err
+				}
+				report.Linked = append(report.Linked, PromotedRecruit{RecruitID: recruit.RecruitID, PlayerID: existingID,
+					Name: recruit.PlayerName, Position: recruit.Position})
+			}
+			continue
+		}
+
+		// Recrutas do ensino médio chegam como FR; transferências mantêm o ano informado
+		classYear, err := ParseClassYear(recruit.Class)
 		if err != nil {
-			fmt.Printf("Erro ao promover recruta: %v\n", err)
+			fmt.Printf("Ano acadêmico inválido para %s, usando FR: %v\n", recruit.PlayerName, err)
+			classYear = models.ClassFreshman
+		}
+
+		// O dev trait do recrutamento entra oculto até ser revelado (RevealDevTrait)
+		devTrait, err := NormalizeDevTrait(recruit.DevTrait)
+		if err != nil {
+			devTrait = ""
+		}
+
+		// Cada promoção é conferida com as regras de elenco; recrutas que estourariam um limite
+		// ficam fora do elenco e podem ser promovidos depois de abrir espaço
+		var playerID int64
+		err = applyRosterRules(tx, []int{recruit.TeamID}, func(tx *sql.Tx) error {
+			// O jogador herda a pessoa do recruta; recrutas antigos sem pessoa ganham uma agora
+			if recruit.PersonID == nil {
+				personID, err := createPerson(tx, recruit.PlayerName)
+				if err != nil {
+					return err
+				}
+				if _, err := tx.Exec("UPDATE recruits SET person_id = ? WHERE recruit_id = ?", personID, recruit.RecruitID); err != nil {
+					return err
+				}
+				recruit.PersonID = &personID
+			}
+
+			result, err := tx.Exec(`
+                INSERT INTO players (name, position, overall, games_played, games_started, snaps_played, class_year, team_id, recruitment_source, archetype, dev_trait,
+                    active, recruit_id, person_id, height, weight, hometown, home_state, tendency)
+                VALUES (?, ?, ?, 0, 0, 0, ?, ?, ?, ?, NULLIF(?, ''), 1, ?, ?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), NULLIF(?, ''));
+            `, recruit.PlayerName, recruit.Position, recruit.Overall, classYear, recruit.TeamID, recruit.RecruitmentSource, recruit.Archetype, devTrait,
+				recruit.RecruitID, *recruit.PersonID, recruit.Height, recruit.Weight, recruit.Hometown, recruit.HomeState, recruit.Tendency)
+			if err != nil {
+				fmt.Printf("Erro ao promover recruta: %v\n", err)
+				return err
+			}
+			playerID, err = result.LastInsertId()
+			if err != nil {
+				return err
+			}
+
+			// Copiar os atributos do recruta para o novo jogador
+			_, err = tx.Exec(`
+                INSERT INTO player_attributes (player_id, attribute, value)
+                SELECT ?, attribute, value FROM recruit_attributes WHERE recruit_id = ?
+            `, playerID, recruit.RecruitID)
+			if err != nil {
+				fmt.Printf("Erro ao copiar atributos do recruta: %v\n", err)
+			}
 			return err
+		})
+		var ruleErr *RosterRuleError
+		if errors.As(err, &ruleErr) {
+			skip.Reason = "regras de elenco violadas"
+			skip.Violations = ruleErr.Violations
+			report.Skipped = append(report.Skipped, skip)
+			continue
+		}
+		if err != nil {
+			return report, nil, err
 		}
-	}
 
-	_, err = database.DB.Exec("DELETE FROM recruits WHERE recruitment_year = ?", recruitmentYear)
-	if err != nil {
-		fmt.Printf("Erro ao remover recrutas promovidos: %v\n", err)
-		return err
+		// O overall de assinatura é o ponto de partida do histórico de ratings
+		err = recordPlayerRating(tx, models.PlayerRating{PlayerID: int(playerID), Year: recruitmentYear, Overall: recruit.Overall})
+		if err != nil {
+			return report, nil, err
+		}
+		if devTrait != "" {
+			err = addDevTraitEvent(tx, models.DevTraitEvent{PlayerID: int(playerID), Year: recruitmentYear, Event: "signed", DevTrait: devTrait})
+			if err != nil {
+				return report, nil, err
+			}
+		}
+		report.Promoted = append(report.Promoted, PromotedRecruit{RecruitID: recruit.RecruitID, PlayerID: int(playerID),
+			Name: recruit.PlayerName, Position: recruit.Position})
+		promotedTeams[recruit.TeamID] = append(promotedTeams[recruit.TeamID], int(playerID))
 	}
+	return report, promotedTeams, nil
+}
 
+// addJerseySuggestions sugere números para os recém-promovidos, que ainda não têm número na
+// temporada. As sugestões de cada time consideram os números já em uso e os aposentados. Lê o
+// banco fora da transação, então deve ser chamada depois do commit da promoção
+func addJerseySuggestions(report *PromotionReport, promotedTeams map[int][]int) error {
+	teamIDs := make([]int, 0, len(promotedTeams))
+	for teamID := range promotedTeams {
+		teamIDs = append(teamIDs, teamID)
+	}
+	sort.Ints(teamIDs)
+	for _, teamID := range teamIDs {
+		promoted := make(map[int]bool)
+		for _, playerID := range promotedTeams[teamID] {
+			promoted[playerID] = true
+		}
+		suggestions, err := GetJerseySuggestions(teamID, report.Year)
+		if err != nil {
+			return err
+		}
+		for _, suggestion := range suggestions {
+			if promoted[suggestion.PlayerID] {
+				report.JerseySuggestions = append(report.JerseySuggestions, suggestion)
+			}
+		}
+	}
 	return nil
 }
 
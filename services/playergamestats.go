@@ -4,6 +4,7 @@ import (
 	"dynastyTracker/database"
 	"dynastyTracker/models"
 	"fmt"
+	"strings"
 )
 
 type PlayerGameStats struct {
@@ -19,17 +20,182 @@ type PlayerGameStats struct {
 	RushingTDs    int `json:"rushing_tds"`
 }
 
-// Função para adicionar estatísticas de jogo para um jogador
-func AddPlayerGameStats(stats models.PlayerGameStats) error {
+// ValidatePlayerGameStats retorna avisos sobre as estatísticas, como jogador lesionado na semana do jogo
+func ValidatePlayerGameStats(stats models.PlayerGameStats) ([]string, error) {
+	var warnings []string
+
+	var year, week int
+	err := database.DB.QueryRow("SELECT year, week FROM schedule WHERE id = ?", stats.ScheduleID).Scan(&year, &week)
+	if err != nil {
+		fmt.Printf("Erro ao buscar jogo: %v\n", err)
+		return nil, err
+	}
+
+	injury, err := getActiveInjury(stats.PlayerID, year, week)
+	if err != nil {
+		return nil, err
+	}
+	if injury != nil {
+		warnings = append(warnings, fmt.Sprintf("jogador %d estava lesionado (%s - %s) na semana %d de %d",
+			stats.PlayerID, injury.InjuryType, injury.BodyPart, week, year))
+	}
+
+	return warnings, nil
+}
+
+// productionByPlayer soma jogos e produção ofensiva de cada jogador, para uso em LEFT JOIN. Jardas
+// e TDs de scrimmage (corrida e recepção) ficam separados dos de passe, que dominariam qualquer
+// soma com um QB. Colunas não preenchidas contam como zero
+const productionByPlayer = `(
+            SELECT player_id,
+                COUNT(DISTINCT schedule_id) AS games,
+                SUM(COALESCE(rushing_yards, 0) + COALESCE(receiving_yards, 0)) AS scrimmage_yards,
+                SUM(COALESCE(rushing_tds, 0) + COALESCE(receiving_tds, 0)) AS scrimmage_tds,
+                SUM(COALESCE(passing_yards, 0)) AS passing_yards,
+                SUM(COALESCE(passing_tds, 0)) AS passing_tds
+            FROM playergamestats
+            GROUP BY player_id
+        )`
+
+// Função para adicionar estatísticas de jogo para um jogador. Retorna os avisos de validação
+func AddPlayerGameStats(stats models.PlayerGameStats) ([]string, error) {
+	warnings, err := ValidatePlayerGameStats(stats)
+	if err != nil {
+		return nil, err
+	}
+
 	query := `
         INSERT INTO playergamestats (player_id, schedule_id, completions, pass_attempts, passing_yards, passing_tds, interceptions, rush_attempts, rushing_yards, rushing_tds)
         VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
     `
 
-	_, err := database.DB.Exec(query, stats.PlayerID, stats.ScheduleID, stats.Completions, stats.PassAttempts, stats.PassingYards, stats.PassingTDs, stats.Interceptions, stats.RushAttempts, stats.RushingYards, stats.RushingTDs)
+	_, err = database.DB.Exec(query, stats.PlayerID, stats.ScheduleID, stats.Completions, stats.PassAttempts, stats.PassingYards, stats.PassingTDs, stats.Interceptions, stats.RushAttempts, stats.RushingYards, stats.RushingTDs)
 	if err != nil {
 		fmt.Printf("Erro ao adicionar estatísticas do jogo: %v\n", err)
-		return err
+		return nil, err
+	}
+	return warnings, nil
+}
+
+type PlayerGameLogEntry struct {
+	ScheduleID      int      `json:"schedule_id"`
+	Year            int      `json:"year"`
+	Week            int      `json:"week"`
+	Opponent        string   `json:"opponent"`
+	OpponentRanking int      `json:"opponent_ranking"`
+	Site            string   `json:"site"`
+	Result          string   `json:"result"`
+	TeamPoints      int      `json:"team_points"`
+	OpponentPoints  int      `json:"opponent_points"`
+	Completions     int      `json:"completions"`
+	PassAttempts    int      `json:"pass_attempts"`
+	PassingYards    int      `json:"passing_yards"`
+	PassingTDs      int      `json:"passing_tds"`
+	Interceptions   int      `json:"interceptions"`
+	RushAttempts    int      `json:"rush_attempts"`
+	RushingYards    int      `json:"rushing_yards"`
+	RushingTDs      int      `json:"rushing_tds"`
+	Receptions      int      `json:"receptions"`
+	ReceivingYards  int      `json:"receiving_yards"`
+	ReceivingTDs    int      `json:"receiving_tds"`
+	CareerHighs     []string `json:"career_highs,omitempty"` // Categorias em que este foi o melhor jogo da carreira
+}
+
+type PlayerGameLog struct {
+	PlayerID    int                  `json:"player_id"`
+	Games       []PlayerGameLogEntry `json:"games"`
+	CareerHighs map[string]int       `json:"career_highs"` // Melhor marca da carreira por categoria
+}
+
+// Categorias consideradas para os recordes pessoais de um jogo
+var careerHighCategories = []string{
+	"completions", "passing_yards", "passing_tds", "rushing_yards", "rushing_tds",
+	"receptions", "receiving_yards", "receiving_tds",
+}
+
+func (game PlayerGameLogEntry) statValue(category string) int {
+	switch category {
+	case "completions":
+		return game.Completions
+	case "passing_yards":
+		return game.PassingYards
+	case "passing_tds":
+		return game.PassingTDs
+	case "rushing_yards":
+		return game.RushingYards
+	case "rushing_tds":
+		return game.RushingTDs
+	case "receptions":
+		return game.Receptions
+	case "receiving_yards":
+		return game.ReceivingYards
+	case "receiving_tds":
+		return game.ReceivingTDs
+	}
+	return 0
+}
+
+// GetPlayerGameLog lista as estatísticas do jogador jogo a jogo com o contexto do adversário,
+// filtrando por temporada e adversário. Os recordes pessoais consideram a carreira inteira,
+// mesmo quando há filtro
+func GetPlayerGameLog(playerID int, year int, opponent string) (PlayerGameLog, error) {
+	gameLog := PlayerGameLog{PlayerID: playerID, CareerHighs: make(map[string]int)}
+
+	rows, err := database.DB.Query(`
+        SELECT s.id, s.year, s.week, s.opponent, COALESCE(s.opponent_ranking, 0), COALESCE(s.site, ''),
+            COALESCE(s.result, ''), COALESCE(s.team_points, 0), COALESCE(s.opponent_points, 0),
+            COALESCE(g.completions, 0), COALESCE(g.pass_attempts, 0), COALESCE(g.passing_yards, 0),
+            COALESCE(g.passing_tds, 0), COALESCE(g.interceptions, 0), COALESCE(g.rush_attempts, 0),
+            COALESCE(g.rushing_yards, 0), COALESCE(g.rushing_tds, 0), COALESCE(g.receptions, 0),
+            COALESCE(g.receiving_yards, 0), COALESCE(g.receiving_tds, 0)
+        FROM playergamestats g
+        JOIN schedule s ON g.schedule_id = s.id
+        WHERE g.player_id = ?
+        ORDER BY s.year, s.week
+    `, playerID)
+	if err != nil {
+		fmt.Printf("Erro ao executar consulta: %v\n", err)
+		return gameLog, err
+	}
+	defer rows.Close()
+
+	var games []PlayerGameLogEntry
+	for rows.Next() {
+		var game PlayerGameLogEntry
+		err := rows.Scan(&game.ScheduleID, &game.Year, &game.Week, &game.Opponent, &game.OpponentRanking, &game.Site,
+			&game.Result, &game.TeamPoints, &game.OpponentPoints,
+			&game.Completions, &game.PassAttempts, &game.PassingYards, &game.PassingTDs, &game.Interceptions,
+			&game.RushAttempts, &game.RushingYards, &game.RushingTDs,
+			&game.Receptions, &game.ReceivingYards, &game.ReceivingTDs)
+		if err != nil {
+			fmt.Printf("Erro ao escanear resultados: %v\n", err)
+			return gameLog, err
+		}
+		games = append(games, game)
+	}
+
+	for _, game := range games {
+		for _, category := range careerHighCategories {
+			if value := game.statValue(category); value > gameLog.CareerHighs[category] {
+				gameLog.CareerHighs[category] = value
+			}
+		}
+	}
+
+	for _, game := range games {
+		if year > 0 && game.Year != year {
+			continue
+		}
+		if opponent != "" && !strings.EqualFold(game.Opponent, opponent) {
+			continue
+		}
+		// Empates também são marcados: o jogador igualou a melhor marca da carreira
+		for _, category := range careerHighCategories {
+			if high := gameLog.CareerHighs[category]; high > 0 && game.statValue(category) == high {
+				game.CareerHighs = append(game.CareerHighs, category)
+			}
+		}
+		gameLog.Games = append(gameLog.Games, game)
 	}
-	return nil
+	return gameLog, nil
 }
@@ -0,0 +1,211 @@
+package services
+
+import (
+	"dynastyTracker/database"
+	"dynastyTracker/models"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Elenco ideal usado enquanto o time não configura o seu (80 jogadores)
+var defaultRosterComposition = []models.PositionTarget{
+	{Position: "QB", Ideal: 4}, {Position: "HB", Ideal: 5}, {Position: "FB", Ideal: 1},
+	{Position: "WR", Ideal: 9}, {Position: "TE", Ideal: 4},
+	{Position: "LT", Ideal: 3}, {Position: "LG", Ideal: 3}, {Position: "C", Ideal: 3}, {Position: "RG", Ideal: 3}, {Position: "RT", Ideal: 3},
+	{Position: "LEDG", Ideal: 4}, {Position: "REDG", Ideal: 4}, {Position: "DT", Ideal: 6},
+	{Position: "SAM", Ideal: 3}, {Position: "MIKE", Ideal: 3}, {Position: "WILL", Ideal: 3},
+	{Position: "CB", Ideal: 9}, {Position: "FS", Ideal: 4}, {Position: "SS", Ideal: 4},
+	{Position: "K", Ideal: 1}, {Position: "P", Ideal: 1},
+}
+
+// Overall a partir do qual um jogador elegível (JR ou mais velho) provavelmente se declara ao draft
+const likelyDeclareOverall = 85
+
+// Peso da falta de jogadores na temporada seguinte (quem será SR) na nota e nas metas
+const followingYearWeight = 0.5
+
+// GetRosterComposition devolve o elenco ideal do time ou o padrão
+func GetRosterComposition(teamID int) (models.RosterComposition, error) {
+	composition := models.RosterComposition{TeamID: teamID}
+
+	rows, err := database.DB.Query("SELECT position, ideal FROM roster_composition WHERE team_id = ? ORDER BY position", teamID)
+	if err != nil {
+		fmt.Printf("Erro ao buscar elenco ideal: %v\n", err)
+		return composition, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var target models.PositionTarget
+		if err := rows.Scan(&target.Position, &target.Ideal); err != nil {
+			return composition, err
+		}
+		composition.Positions = append(composition.Positions, target)
+	}
+
+	if len(composition.Positions) == 0 {
+		composition.IsDefault = true
+		composition.Positions = defaultRosterComposition
+	}
+	return composition, nil
+}
+
+// ErrInvalidRosterComposition indica posições vazias, repetidas ou com quantidade negativa
+var ErrInvalidRosterComposition = errors.New("elenco ideal inválido")
+
+// SaveRosterComposition substitui o elenco ideal do time
+func SaveRosterComposition(composition models.RosterComposition) error {
+	seen := make(map[string]bool)
+	for _, target := range composition.Positions {
+		if target.Position == "" || target.Ideal < 0 {
+			return fmt.Errorf("%w: posição ou quantidade ideal inválida: %q (%d)", ErrInvalidRosterComposition, target.Position, target.Ideal)
+		}
+		if seen[target.Position] {
+			return fmt.Errorf("%w: posição repetida: %s", ErrInvalidRosterComposition, target.Position)
+		}
+		seen[target.Position] = true
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM roster_composition WHERE team_id = ?", composition.TeamID); err != nil {
+		return err
+	}
+	for _, target := range composition.Positions {
+		_, err := tx.Exec("INSERT INTO roster_composition (team_id, position, ideal) VALUES (?, ?, ?)",
+			composition.TeamID, target.Position, target.Ideal)
+		if err != nil {
+			fmt.Printf("Erro ao gravar elenco ideal: %v\n", err)
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+type PositionalNeed struct {
+	Position           string  `json:"position"`
+	Ideal              int     `json:"ideal"`
+	Current            int     `json:"current"`               // Jogadores ativos hoje
+	Graduating         int     `json:"graduating"`            // Elegibilidade termina nesta temporada
+	Declared           int     `json:"declared"`              // Já declarados ao draft
+	LikelyDeclare      int     `json:"likely_declare"`        // Elegíveis com overall alto, sem declaração
+	InPortal           int     `json:"in_portal"`             // No portal de transferências, ainda no elenco
+	Committed          int     `json:"committed"`             // Recrutas e transferências assinados para a próxima temporada
+	Projected          int     `json:"projected"`             // Elenco da posição após a virada
+	LeavingFollowing   int     `json:"leaving_following"`     // Projetados que serão SR na próxima temporada
+	Gap                int     `json:"gap"`                   // Falta para o ideal após a virada
+	FollowingYearGap   int     `json:"following_year_gap"`    // Falta adicional quando os futuros SR saírem
+	Target             int     `json:"target"`                // Recrutas ainda a buscar
+	Score              float64 `json:"score"`                 // 0 a 100; maior = necessidade mais urgente
+	CappedByRosterRule bool    `json:"capped_by_roster_rule"` // Meta limitada pelo máximo da posição
+}
+
+type PositionalNeeds struct {
+	TeamID                int                      `json:"team_id"`
+	Year                  int                      `json:"year"`
+	Composition           models.RosterComposition `json:"composition"`
+	ProjectedScholarships int                      `json:"projected_scholarships"`
+	ScholarshipsAvailable int                      `json:"scholarships_available"`
+	TotalTarget           int                      `json:"total_target"`
+	Positions             []PositionalNeed         `json:"positions"`
+	InvalidClassYears     []InvalidClassYear       `json:"invalid_class_years"` // Jogadores fora da projeção
+}
+
+// GetPositionalNeeds compara o elenco projetado para a próxima temporada (projectRollover) com o
+// elenco ideal. Além da projeção, desconta as prováveis declarações ao draft e olha um ano à
+// frente (quem será SR)
+func GetPositionalNeeds(teamID int, year int) (PositionalNeeds, error) {
+	needs := PositionalNeeds{TeamID: teamID, Year: year}
+
+	composition, err := GetRosterComposition(teamID)
+	if err != nil {
+		return needs, err
+	}
+	needs.Composition = composition
+
+	byPosition := make(map[string]*PositionalNeed)
+	need := func(position string) *PositionalNeed {
+		n, ok := byPosition[position]
+		if !ok {
+			n = &PositionalNeed{Position: position}
+			byPosition[position] = n
+		}
+		return n
+	}
+	for _, target := range composition.Positions {
+		need(target.Position).Ideal = target.Ideal
+	}
+
+	projection, err := projectRollover(teamID, year)
+	if err != nil {
+		return needs, err
+	}
+	needs.InvalidClassYears = projection.invalid
+
+	for _, p := range projection.players {
+		n := need(p.position)
+		n.Current++
+		switch {
+		case p.outcome == projectionDeclared:
+			n.Declared++
+		case p.outcome == projectionGraduating:
+			n.Graduating++
+		case p.outcome == projectionInPortal:
+			n.InPortal++
+		case p.overall >= likelyDeclareOverall && remainingEligibility(p.classYear) <= 2:
+			n.LikelyDeclare++
+		default:
+			n.Projected++
+			if !p.walkOn {
+				needs.ProjectedScholarships++
+			}
+			if p.newClass == models.ClassSenior || p.newClass == models.ClassRedshirtSenior {
+				n.LeavingFollowing++
+			}
+		}
+	}
+	for _, r := range projection.recruits {
+		n := need(r.position)
+		n.Committed++
+		n.Projected++
+		needs.ProjectedScholarships++
+	}
+
+	rules, err := GetRosterRules(teamID)
+	if err != nil {
+		return needs, err
+	}
+	maxByPosition := make(map[string]int)
+	for _, limit := range rules.PositionLimits {
+		maxByPosition[limit.Position] = limit.Max
+	}
+
+	for _, n := range byPosition {
+		n.Gap = max(0, n.Ideal-n.Projected)
+		n.FollowingYearGap = max(0, n.Ideal-(n.Projected-n.LeavingFollowing)) - n.Gap
+		n.Target = n.Gap + int(math.Ceil(followingYearWeight*float64(n.FollowingYearGap)))
+		if limit := maxByPosition[n.Position]; limit > 0 && n.Projected+n.Target > limit {
+			n.Target = max(0, limit-n.Projected)
+			n.CappedByRosterRule = true
+		}
+		if n.Ideal > 0 {
+			n.Score = math.Min(100, 100*(float64(n.Gap)+followingYearWeight*float64(n.FollowingYearGap))/float64(n.Ideal))
+		}
+		needs.TotalTarget += n.Target
+		needs.Positions = append(needs.Positions, *n)
+	}
+	needs.ScholarshipsAvailable = max(0, rules.ScholarshipCap-needs.ProjectedScholarships)
+
+	sort.Slice(needs.Positions, func(i, j int) bool {
+		if needs.Positions[i].Score != needs.Positions[j].Score {
+			return needs.Positions[i].Score > needs.Positions[j].Score
+		}
+		return needs.Positions[i].Position < needs.Positions[j].Position
+	})
+	return needs, nil
+}
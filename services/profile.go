@@ -0,0 +1,143 @@
+package services
+
+import (
+	"database/sql"
+	"dynastyTracker/database"
+	"dynastyTracker/models"
+	"fmt"
+)
+
+type PlayerCareerTotals struct {
+	Seasons        int `json:"seasons"`
+	Games          int `json:"games"`
+	Completions    int `json:"completions"`
+	PassingYards   int `json:"passing_yards"`
+	PassingTDs     int `json:"passing_tds"`
+	RushingYards   int `json:"rushing_yards"`
+	RushingTDs     int `json:"rushing_tds"`
+	ReceivingYards int `json:"receiving_yards"`
+	ReceivingTDs   int `json:"receiving_tds"`
+}
+
+type RecordBookStanding struct {
+	Category        string  `json:"category"`
+	Career          int     `json:"career"`
+	Record          int     `json:"record"`
+	PercentOfRecord float64 `json:"percent_of_record"`
+	Rank            int     `json:"rank"` // Posição que o jogador ocuparia no livro de recordes
+}
+
+type PlayerProfile struct {
+	Player     models.Player          `json:"player"`
+	Recruiting *models.Recruit        `json:"recruiting"` // nil = jogador sem recruta de origem
+	Ratings    []models.PlayerRating  `json:"ratings"`
+	Seasons    []PlayerYearlyStats    `json:"seasons"`
+	Career     PlayerCareerTotals     `json:"career"`
+	GameLog    PlayerGameLog          `json:"game_log"`
+	Awards     TrophyCase             `json:"awards"`
+	RecordBook []RecordBookStanding   `json:"record_book"`
+	DevTraits  []models.DevTraitEvent `json:"dev_traits"`
+}
+
+// GetPlayerProfile reúne em uma resposta tudo o que a página do jogador precisa
+func GetPlayerProfile(playerID int) (PlayerProfile, error) {
+	var profile PlayerProfile
+
+	player, err := GetPlayer(playerID)
+	if err != nil {
+		return profile, err
+	}
+	profile.Player = player
+
+	if player.RecruitID != nil {
+		recruit, err := GetRecruit(*player.RecruitID)
+		if err != nil && err != sql.ErrNoRows {
+			return profile, err
+		}
+		if err == nil {
+			profile.Recruiting = &recruit
+		}
+	}
+
+	if profile.Ratings, err = GetPlayerRatingsHistory(playerID); err != nil {
+		return profile, err
+	}
+	if profile.Seasons, err = GetPlayerCareerProgression(playerID); err != nil {
+		return profile, err
+	}
+	if profile.GameLog, err = GetPlayerGameLog(playerID, 0, ""); err != nil {
+		return profile, err
+	}
+	if profile.Awards, err = GetPlayerTrophyCase(playerID); err != nil {
+		return profile, err
+	}
+	if profile.DevTraits, err = GetDevTraitHistory(playerID); err != nil {
+		return profile, err
+	}
+
+	profile.Career = sumCareerTotals(profile.Seasons, len(profile.GameLog.Games))
+
+	if profile.RecordBook, err = getRecordBookStanding(profile.Career); err != nil {
+		return profile, err
+	}
+	return profile, nil
+}
+
+// sumCareerTotals soma as temporadas do jogador; temporadas só com prêmios não contam
+func sumCareerTotals(seasons []PlayerYearlyStats, games int) PlayerCareerTotals {
+	career := PlayerCareerTotals{Games: games}
+	for _, season := range seasons {
+		if season.Games > 0 {
+			career.Seasons++
+		}
+		career.Completions += season.Completions
+		career.PassingYards += season.PassingYards
+		career.PassingTDs += season.PassingTDs
+		career.RushingYards += season.RushingYards
+		career.RushingTDs += season.RushingTDs
+		career.ReceivingYards += season.ReceivingYards
+		career.ReceivingTDs += season.ReceivingTDs
+	}
+	return career
+}
+
+// getRecordBookStanding compara os totais de carreira com os recordes históricos
+func getRecordBookStanding(career PlayerCareerTotals) ([]RecordBookStanding, error) {
+	records, err := GetCareerRecords()
+	if err != nil {
+		return nil, err
+	}
+
+	// Quantos recordes históricos estão à frente do jogador em cada categoria
+	var ahead [5]int
+	err = database.DB.QueryRow(`
+        SELECT
+            COALESCE(SUM(completions > ?), 0),
+            COALESCE(SUM(passing_yards > ?), 0),
+            COALESCE(SUM(touchdowns > ?), 0),
+            COALESCE(SUM(rush_yards > ?), 0),
+            COALESCE(SUM(receiving_yards > ?), 0)
+        FROM historicalrecords
+    `, career.Completions, career.PassingYards, career.PassingTDs, career.RushingYards, career.ReceivingYards).
+		Scan(&ahead[0], &ahead[1], &ahead[2], &ahead[3], &ahead[4])
+	if err != nil {
+		fmt.Printf("Erro ao executar consulta: %v\n", err)
+		return nil, err
+	}
+
+	standing := func(category string, value int, record int, rank int) RecordBookStanding {
+		s := RecordBookStanding{Category: category, Career: value, Record: record, Rank: rank + 1}
+		if record > 0 {
+			s.PercentOfRecord = float64(value) / float64(record) * 100
+		}
+		return s
+	}
+
+	return []RecordBookStanding{
+		standing("completions", career.Completions, records.MaxCompletions, ahead[0]),
+		standing("passing_yards", career.PassingYards, records.MaxPassingYards, ahead[1]),
+		standing("passing_tds", career.PassingTDs, records.MaxPassingTDs, ahead[2]),
+		standing("rushing_yards", career.RushingYards, records.MaxRushingYards, ahead[3]),
+		standing("receiving_yards", career.ReceivingYards, records.MaxReceivingYards, ahead[4]),
+	}, nil
+}
@@ -0,0 +1,425 @@
+package services
+
+import (
+	"database/sql"
+	"dynastyTracker/database"
+	"dynastyTracker/models"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrProspectNotFound = errors.New("alvo não encontrado")
+	ErrProspectStage    = errors.New("a etapa do alvo não permite a operação")
+	ErrInvalidProspect  = errors.New("dados do alvo inválidos")
+)
+
+// Etapas do quadro de recrutamento, na ordem em que a disputa avança
+const (
+	StageScouted        = "scouted"
+	StageOffered        = "offered"
+	StageTop8           = "top8"
+	StageTop5           = "top5"
+	StageTop3           = "top3"
+	StageVisitScheduled = "visit_scheduled"
+	StageCommitted      = "committed"
+	StageSigned         = "signed"
+	StageLost           = "lost"
+)
+
+var prospectStages = []string{StageScouted, StageOffered, StageTop8, StageTop5, StageTop3,
+	StageVisitScheduled, StageCommitted, StageSigned, StageLost}
+
+func validateProspectStage(stage string) error {
+	for _, s := range prospectStages {
+		if s == stage {
+			return nil
+		}
+	}
+	return fmt.Errorf("etapa inválida: %s (use %s)", stage, strings.Join(prospectStages, ", "))
+}
+
+// Totais de esforço vêm do histórico semanal
+const prospectColumns = `
+    p.prospect_id, p.team_id, p.year, p.name, p.position, p.stars, p.national_rank, p.position_rank,
+    COALESCE(p.hometown, ''), COALESCE(p.home_state, ''), p.stage, p.visit_schedule_id, s.week, p.recruit_id,
+    COALESCE((SELECT SUM(w.hours) FROM prospect_weeks w WHERE w.prospect_id = p.prospect_id), 0),
+    COALESCE((SELECT SUM(w.points) FROM prospect_weeks w WHERE w.prospect_id = p.prospect_id), 0),
+    COALESCE(p.notes, '')
+    FROM prospects p
+    LEFT JOIN schedule s ON s.id = p.visit_schedule_id`
+
+func scanProspect(row rowScanner) (models.Prospect, error) {
+	var p models.Prospect
+	err := row.Scan(&p.ProspectID, &p.TeamID, &p.Year, &p.Name, &p.Position, &p.Stars, &p.NationalRank, &p.PositionRank,
+		&p.Hometown, &p.HomeState, &p.Stage, &p.VisitScheduleID, &p.VisitWeek, &p.RecruitID,
+		&p.TotalHours, &p.TotalPoints, &p.Notes)
+	return p, err
+}
+
+// AddProspect coloca um novo alvo no quadro. Sem etapa informada, começa como scouted
+func AddProspect(prospect models.Prospect) error {
+	if prospect.Stage == "" {
+		prospect.Stage = StageScouted
+	}
+	if err := validateProspectStage(prospect.Stage); err != nil {
+		return err
+	}
+	if prospect.Stage == StageSigned || prospect.Stage == StageVisitScheduled {
+		return fmt.Errorf("use a assinatura ou o agendamento de visita para a etapa %s", prospect.Stage)
+	}
+
+	_, err := database.DB.Exec(`
+        INSERT INTO prospects (team_id, year, name, position, stars, national_rank, position_rank, hometown, home_state, stage, notes)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+    `, prospect.TeamID, prospect.Year, prospect.Name, prospect.Position, prospect.Stars, prospect.NationalRank,
+		prospect.PositionRank, prospect.Hometown, prospect.HomeState, prospect.Stage, prospect.Notes)
+	if err != nil {
+		fmt.Printf("Erro ao adicionar alvo de recrutamento: %v\n", err)
+		return err
+	}
+	return nil
+}
+
+// GetProspect obtém um alvo do quadro pelo ID
+func GetProspect(id int) (models.Prospect, error) {
+	prospect, err := scanProspect(database.DB.QueryRow("SELECT "+prospectColumns+" WHERE p.prospect_id = ?", id))
+	if err != nil {
+		return prospect, err
+	}
+	prospect.Competitors, err = GetProspectCompetitors(id)
+	return prospect, err
+}
+
+type ProspectPage struct {
+	Prospects []models.Prospect `json:"prospects"`
+	PageInfo
+}
+
+// Campos aceitos na ordenação do quadro de recrutamento
+var prospectSortColumns = map[string]string{
+	"name":          "p.name",
+	"position":      "p.position",
+	"stars":         "p.stars",
+	"national_rank": "p.national_rank",
+	"position_rank": "p.position_rank",
+	"stage":         "p.stage",
+	"state":         "p.home_state",
+}
+
+// GetProspectsWithFilters lista o quadro do time no ano, opcionalmente filtrado por etapa,
+// paginado e ordenado
+func GetProspectsWithFilters(teamID int, year int, stage string, options ListOptions) (ProspectPage, error) {
+	options = options.normalize()
+	page := ProspectPage{Prospects: []models.Prospect{}}
+
+	where := " WHERE 1=1"
+	var args []interface{}
+	if teamID > 0 {
+		where += " AND p.team_id = ?"
+		args = append(args, teamID)
+	}
+	if year > 0 {
+		where += " AND p.year = ?"
+		args = append(args, year)
+	}
+	if stage != "" {
+		where += " AND p.stage = ?"
+		args = append(args, stage)
+	}
+
+	suffix, pageArgs, err := options.orderAndLimit(prospectSortColumns, "p.national_rank = 0, p.national_rank, p.name", "p.prospect_id")
+	if err != nil {
+		return page, err
+	}
+
+	var total int
+	if err := database.DB.QueryRow("SELECT COUNT(*) FROM prospects p"+where, args...).Scan(&total); err != nil {
+		fmt.Printf("Erro ao contar alvos: %v\n", err)
+		return page, err
+	}
+	page.PageInfo = options.pageInfo(total)
+
+	rows, err := database.DB.Query("SELECT "+prospectColumns+where+suffix, append(args, pageArgs...)...)
+	if err != nil {
+		fmt.Printf("Erro ao executar consulta: %v\n", err)
+		return page, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		p, err := scanProspect(rows)
+		if err != nil {
+			fmt.Printf("Erro ao escanear resultados: %v\n", err)
+			return page, err
+		}
+		page.Prospects = append(page.Prospects, p)
+	}
+	return page, nil
+}
+
+// UpdateProspect atualiza os dados do alvo. Visita e assinatura têm fluxos próprios
+// (ScheduleProspectVisit e SignProspect) e não mudam por aqui
+func UpdateProspect(prospect models.Prospect) error {
+	current, err := GetProspect(prospect.ProspectID)
+	if err != nil {
+		return err
+	}
+	if current.Stage == StageSigned {
+		return fmt.Errorf("alvo já assinou; edite o recruta %d", intValue(current.RecruitID))
+	}
+	if err := validateProspectStage(prospect.Stage); err != nil {
+		return err
+	}
+	if prospect.Stage == StageSigned || (prospect.Stage == StageVisitScheduled && current.VisitScheduleID == nil) {
+		return fmt.Errorf("use a assinatura ou o agendamento de visita para a etapa %s", prospect.Stage)
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+        UPDATE prospects SET name = ?, position = ?, stars = ?, national_rank = ?, position_rank = ?,
+            hometown = ?, home_state = ?, stage = ?, notes = ?
+        WHERE prospect_id = ?
+    `, prospect.Name, prospect.Position, prospect.Stars, prospect.NationalRank, prospect.PositionRank,
+		prospect.Hometown, prospect.HomeState, prospect.Stage, prospect.Notes, prospect.ProspectID)
+	if err != nil {
+		fmt.Printf("Erro ao atualizar alvo de recrutamento: %v\n", err)
+		return err
+	}
+	if prospect.Stage != current.Stage {
+		if err := recordStageSnapshot(tx, current.ProspectID, current.Year, prospect.Stage); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// recordStageSnapshot guarda a nova etapa no histórico semanal, na última semana registrada do alvo
+// (semana 0 antes do primeiro registro), sem alterar as horas e pontos já lançados
+func recordStageSnapshot(tx sqlRunner, prospectID int, year int, stage string) error {
+	var week int
+	err := tx.QueryRow("SELECT COALESCE(MAX(week), 0) FROM prospect_weeks WHERE prospect_id = ?", prospectID).Scan(&week)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`
+        INSERT INTO prospect_weeks (prospect_id, year, week, stage, hours, points)
+        VALUES (?, ?, ?, ?, 0, 0)
+        ON DUPLICATE KEY UPDATE stage = VALUES(stage)
+    `, prospectID, year, week, stage)
+	if err != nil {
+		fmt.Printf("Erro ao registrar etapa no histórico: %v\n", err)
+	}
+	return err
+}
+
+// DeleteProspect remove o alvo do quadro com todo o histórico semanal e as escolas concorrentes
+func DeleteProspect(id int) error {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM prospect_weeks WHERE prospect_id = ?", id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM prospect_competitors WHERE prospect_id = ?", id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM prospects WHERE prospect_id = ?", id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// RecordProspectWeek registra as horas e pontos gastos na semana e guarda a situação do alvo
+// naquele momento. Informar a etapa também avança o alvo no quadro
+func RecordProspectWeek(week models.ProspectWeek) error {
+	prospect, err := GetProspect(week.ProspectID)
+	if err != nil {
+		return fmt.Errorf("alvo não encontrado: %d", week.ProspectID)
+	}
+	if prospect.Stage == StageSigned {
+		return fmt.Errorf("alvo já assinou")
+	}
+	if week.Hours < 0 || week.Points < 0 {
+		return fmt.Errorf("horas e pontos não podem ser negativos")
+	}
+	if week.Stage == "" {
+		week.Stage = prospect.Stage
+	}
+	if err := validateProspectStage(week.Stage); err != nil {
+		return err
+	}
+	if week.Stage == StageSigned {
+		return fmt.Errorf("use a assinatura para a etapa %s", week.Stage)
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+        INSERT INTO prospect_weeks (prospect_id, year, week, stage, hours, points, interest_rank, notes)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+        ON DUPLICATE KEY UPDATE stage = VALUES(stage), hours = VALUES(hours), points = VALUES(points),
+            interest_rank = VALUES(interest_rank), notes = VALUES(notes)
+    `, week.ProspectID, prospect.Year, week.Week, week.Stage, week.Hours, week.Points, week.InterestRank, week.Notes)
+	if err != nil {
+		fmt.Printf("Erro ao registrar semana de recrutamento: %v\n", err)
+		return err
+	}
+	if _, err := tx.Exec("UPDATE prospects SET stage = ? WHERE prospect_id = ?", week.Stage, week.ProspectID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetProspectHistory devolve as semanas registradas do alvo, em ordem
+func GetProspectHistory(prospectID int) ([]models.ProspectWeek, error) {
+	rows, err := database.DB.Query(`
+        SELECT prospect_id, year, week, stage, hours, points, interest_rank, COALESCE(notes, '')
+        FROM prospect_weeks WHERE prospect_id = ? ORDER BY week
+    `, prospectID)
+	if err != nil {
+		fmt.Printf("Erro ao executar consulta: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var weeks []models.ProspectWeek
+	for rows.Next() {
+		var w models.ProspectWeek
+		if err := rows.Scan(&w.ProspectID, &w.Year, &w.Week, &w.Stage, &w.Hours, &w.Points, &w.InterestRank, &w.Notes); err != nil {
+			fmt.Printf("Erro ao escanear resultados: %v\n", err)
+			return nil, err
+		}
+		weeks = append(weeks, w)
+	}
+	return weeks, nil
+}
+
+// ScheduleProspectVisit marca a visita do alvo para um jogo em casa do time no ano do recrutamento
+func ScheduleProspectVisit(prospectID int, scheduleID int) error {
+	prospect, err := GetProspect(prospectID)
+	if err == sql.ErrNoRows {
+		return ErrProspectNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if prospect.Stage == StageSigned || prospect.Stage == StageLost {
+		return fmt.Errorf("%w: não é possível agendar visita para alvo na etapa %s", ErrProspectStage, prospect.Stage)
+	}
+
+	var teamID, year int
+	var site string
+	err = database.DB.QueryRow("SELECT team_id, year, COALESCE(site, '') FROM schedule WHERE id = ?", scheduleID).Scan(&teamID, &year, &site)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("%w: jogo não encontrado: %d", ErrInvalidProspect, scheduleID)
+	}
+	if err != nil {
+		return err
+	}
+	if teamID != prospect.TeamID || year != prospect.Year {
+		return fmt.Errorf("%w: o jogo %d não é do time e ano do recrutamento", ErrInvalidProspect, scheduleID)
+	}
+	if !strings.EqualFold(site, "Home") {
+		return fmt.Errorf("%w: visitas só podem ser agendadas em jogos em casa", ErrInvalidProspect)
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("UPDATE prospects SET visit_schedule_id = ?, stage = ? WHERE prospect_id = ?",
+		scheduleID, StageVisitScheduled, prospectID)
+	if err != nil {
+		fmt.Printf("Erro ao agendar visita: %v\n", err)
+		return err
+	}
+	if err := recordStageSnapshot(tx, prospectID, prospect.Year, StageVisitScheduled); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// SignProspect transforma o alvo em recruta assinado. Os dados do quadro preenchem o que não
+// vier no recruta, e o alvo fica vinculado ao recruta criado
+func SignProspect(prospectID int, recruit models.Recruit) (int, error) {
+	prospect, err := GetProspect(prospectID)
+	if err == sql.ErrNoRows {
+		return 0, ErrProspectNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	if prospect.Stage == StageSigned {
+		return 0, fmt.Errorf("%w: alvo já assinou (recruta %d)", ErrProspectStage, intValue(prospect.RecruitID))
+	}
+
+	recruit.TeamID, recruit.RecruitmentYear = prospect.TeamID, prospect.Year
+	if recruit.PlayerName == "" {
+		recruit.PlayerName = prospect.Name
+	}
+	if recruit.Position == "" {
+		recruit.Position = prospect.Position
+	}
+	if recruit.Stars == 0 {
+		recruit.Stars = prospect.Stars
+	}
+	if recruit.NationalRank == 0 {
+		recruit.NationalRank = prospect.NationalRank
+	}
+	if recruit.PositionRank == 0 {
+		recruit.PositionRank = prospect.PositionRank
+	}
+	if recruit.Hometown == "" {
+		recruit.Hometown = prospect.Hometown
+	}
+	if recruit.HomeState == "" {
+		recruit.HomeState = prospect.HomeState
+	}
+	if recruit.RecruitmentSource == "" {
+		recruit.RecruitmentSource = "High School"
+	}
+
+	// O recruta e o vínculo com o alvo são gravados juntos; a condição na etapa impede assinar duas vezes
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	recruitID, err := addRecruit(tx, recruit)
+	if err != nil {
+		return 0, err
+	}
+	result, err := tx.Exec("UPDATE prospects SET stage = ?, recruit_id = ? WHERE prospect_id = ? AND stage <> ?",
+		StageSigned, recruitID, prospectID, StageSigned)
+	if err != nil {
+		fmt.Printf("Erro ao vincular alvo ao recruta: %v\n", err)
+		return 0, err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return 0, fmt.Errorf("%w: alvo já assinou", ErrProspectStage)
+	}
+	if err := recordStageSnapshot(tx, prospectID, prospect.Year, StageSigned); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return recruitID, nil
+}
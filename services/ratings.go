@@ -0,0 +1,50 @@
+package services
+
+import (
+	"dynastyTracker/database"
+	"dynastyTracker/models"
+	"fmt"
+)
+
+// RecordPlayerRating grava (ou substitui) o overall do jogador na temporada
+func RecordPlayerRating(rating models.PlayerRating) error {
+	return recordPlayerRating(database.DB, rating)
+}
+
+func recordPlayerRating(exec sqlExecer, rating models.PlayerRating) error {
+	_, err := exec.Exec(`
+        INSERT INTO player_ratings (player_id, year, overall)
+        VALUES (?, ?, ?)
+        ON DUPLICATE KEY UPDATE overall = VALUES(overall)
+    `, rating.PlayerID, rating.Year, rating.Overall)
+	if err != nil {
+		fmt.Printf("Erro ao gravar overall do jogador: %v\n", err)
+		return err
+	}
+	return nil
+}
+
+// GetPlayerRatingsHistory retorna a evolução do overall do jogador por temporada
+func GetPlayerRatingsHistory(playerID int) ([]models.PlayerRating, error) {
+	rows, err := database.DB.Query(`
+        SELECT player_id, year, overall FROM player_ratings
+        WHERE player_id = ?
+        ORDER BY year
+    `, playerID)
+	if err != nil {
+		fmt.Printf("Erro ao executar consulta: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ratings []models.PlayerRating
+	for rows.Next() {
+		var rating models.PlayerRating
+		if err := rows.Scan(&rating.PlayerID, &rating.Year, &rating.Overall); err != nil {
+			fmt.Printf("Erro ao escanear resultados: %v\n", err)
+			return nil, err
+		}
+		ratings = append(ratings, rating)
+	}
+	return ratings, nil
+}
@@ -3,19 +3,258 @@ package services
 import (
 	"dynastyTracker/database"
 	"dynastyTracker/models"
+	"errors"
 	"fmt"
 )
 
 // Função para adicionar um recruta à tabela recruits
 func AddRecruit(recruit models.Recruit) error {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := addRecruit(tx, recruit); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// addRecruit insere o recruta, com os atributos, na transação do chamador e devolve o ID gerado
+func addRecruit(tx sqlRunner, recruit models.Recruit) (int, error) {
+	err := ValidateAttributes(recruit.Position, recruit.Archetype, recruit.Attributes)
+	if err != nil {
+		return 0, err
+	}
+
+	// Transferências de volta ou recrutas já conhecidos podem informar a pessoa existente
+	personID, err := resolvePerson(tx, recruit.PersonID, recruit.PlayerName)
+	if err != nil {
+		return 0, err
+	}
+
 	query := `
-        INSERT INTO recruits (player_name, class, position, tendency, position_rank, national_rank, stars, hometown, home_state, height, weight, dev_trait, overall, gem_bust, recruitment_source, recruitment_year, team_id)
-        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+        INSERT INTO recruits (player_name, class, position, tendency, position_rank, national_rank, stars, hometown, home_state, height, weight, dev_trait, overall, gem_bust, recruitment_source, recruitment_year, team_id, archetype, person_id, eligibility_remaining)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
     `
-	_, err := database.DB.Exec(query, recruit.PlayerName, recruit.Class, recruit.Position, recruit.Tendency, recruit.PositionRank, recruit.NationalRank, recruit.Stars, recruit.Hometown, recruit.HomeState, recruit.Height, recruit.Weight, recruit.DevTrait, recruit.Overall, recruit.GemBust, recruit.RecruitmentSource, recruit.RecruitmentYear, recruit.TeamID)
+	result, err := tx.Exec(query, recruit.PlayerName, recruit.Class, recruit.Position, recruit.Tendency, recruit.PositionRank, recruit.NationalRank, recruit.Stars, recruit.Hometown, recruit.HomeState, recruit.Height, recruit.Weight, recruit.DevTrait, recruit.Overall, recruit.GemBust, recruit.RecruitmentSource, recruit.RecruitmentYear, recruit.TeamID, recruit.Archetype, personID, recruit.EligibilityRemaining)
 	if err != nil {
 		fmt.Printf("Erro ao adicionar recruta: %v\n", err)
+		return 0, err
+	}
+
+	recruitID, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	if err := replaceAttributes(tx, "recruit_attributes", "recruit_id", int(recruitID), recruit.Attributes); err != nil {
+		return 0, err
+	}
+	return int(recruitID), nil
+}
+
+const recruitColumns = `
+    recruit_id, player_name, class, position, COALESCE(tendency, ''), position_rank, national_rank, stars,
+    COALESCE(hometown, ''), COALESCE(home_state, ''), height, weight, COALESCE(dev_trait, ''), overall,
+    COALESCE(gem_bust, ''), recruitment_source, recruitment_year, team_id, COALESCE(archetype, ''), person_id, eligibility_remaining`
+
+func scanRecruit(row rowScanner) (models.Recruit, error) {
+	var recruit models.Recruit
+	err := row.Scan(&recruit.RecruitID, &recruit.PlayerName, &recruit.Class, &recruit.Position, &recruit.Tendency,
+		&recruit.PositionRank, &recruit.NationalRank, &recruit.Stars, &recruit.Hometown, &recruit.HomeState,
+		&recruit.Height, &recruit.Weight, &recruit.DevTrait, &recruit.Overall, &recruit.GemBust,
+		&recruit.RecruitmentSource, &recruit.RecruitmentYear, &recruit.TeamID, &recruit.Archetype, &recruit.PersonID,
+		&recruit.EligibilityRemaining)
+	return recruit, err
+}
+
+// GetRecruit obtém um recruta pelo ID, com seus atributos
+func GetRecruit(id int) (models.Recruit, error) {
+	recruit, err := scanRecruit(database.DB.QueryRow("SELECT "+recruitColumns+" FROM recruits WHERE recruit_id = ?", id))
+	if err != nil {
+		return recruit, err
+	}
+
+	attributes, err := loadAttributes("recruit_attributes", "recruit_id", []int{id})
+	if err != nil {
+		return recruit, err
+	}
+	recruit.Attributes = attributes[id]
+	return recruit, nil
+}
+
+// RecruitFilter reúne os filtros da listagem de recrutas; valores zerados não filtram
+type RecruitFilter struct {
+	TeamID   int
+	Year     int
+	Position string
+	Stars    int
+	State    string
+	DevTrait string
+	Source   string
+	GemBust  string
+}
+
+type RecruitPage struct {
+	Recruits []models.Recruit `json:"recruits"`
+	PageInfo
+}
+
+// Campos aceitos na ordenação da listagem de recrutas
+var recruitSortColumns = map[string]string{
+	"name":          "player_name",
+	"position":      "position",
+	"stars":         "stars",
+	"national_rank": "national_rank",
+	"position_rank": "position_rank",
+	"overall":       "overall",
+	"year":          "recruitment_year",
+	"state":         "home_state",
+}
+
+// GetRecruitsWithFilters lista recrutas filtrados, paginados e ordenados
+func GetRecruitsWithFilters(filter RecruitFilter, options ListOptions) (RecruitPage, error) {
+	options = options.normalize()
+	page := RecruitPage{Recruits: []models.Recruit{}}
+
+	where := " WHERE 1=1"
+	var args []interface{}
+	if filter.TeamID > 0 {
+		where += " AND team_id = ?"
+		args = append(args, filter.TeamID)
+	}
+	if filter.Year > 0 {
+		where += " AND recruitment_year = ?"
+		args = append(args, filter.Year)
+	}
+	if filter.Position != "" {
+		where += " AND position = ?"
+		args = append(args, filter.Position)
+	}
+	if filter.Stars > 0 {
+		where += " AND stars = ?"
+		args = append(args, filter.Stars)
+	}
+	if filter.State != "" {
+		where += " AND home_state = ?"
+		args = append(args, filter.State)
+	}
+	if filter.DevTrait != "" {
+		where += " AND dev_trait = ?"
+		args = append(args, filter.DevTrait)
+	}
+	if filter.Source != "" {
+		where += " AND recruitment_source = ?"
+		args = append(args, filter.Source)
+	}
+	if filter.GemBust != "" {
+		where += " AND gem_bust = ?"
+		args = append(args, filter.GemBust)
+	}
+
+	// Sem ordenação informada, os melhores recrutas (menor ranking nacional) vêm primeiro
+	suffix, pageArgs, err := options.orderAndLimit(recruitSortColumns,
+		"recruitment_year DESC, national_rank = 0, national_rank", "recruit_id")
+	if err != nil {
+		return page, err
+	}
+
+	var total int
+	if err := database.DB.QueryRow("SELECT COUNT(*) FROM recruits"+where, args...).Scan(&total); err != nil {
+		fmt.Printf("Erro ao contar recrutas: %v\n", err)
+		return page, err
+	}
+	page.PageInfo = options.pageInfo(total)
+
+	rows, err := database.DB.Query("SELECT "+recruitColumns+" FROM recruits"+where+suffix, append(args, pageArgs...)...)
+	if err != nil {
+		fmt.Printf("Erro ao executar consulta: %v\n", err)
+		return page, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		recruit, err := scanRecruit(rows)
+		if err != nil {
+			fmt.Printf("Erro ao escanear resultados: %v\n", err)
+			return page, err
+		}
+		page.Recruits = append(page.Recruits, recruit)
+		ids = append(ids, recruit.RecruitID)
+	}
+
+	attributes, err := loadAttributes("recruit_attributes", "recruit_id", ids)
+	if err != nil {
+		return page, err
+	}
+	for i := range page.Recruits {
+		page.Recruits[i].Attributes = attributes[page.Recruits[i].RecruitID]
+	}
+	return page, nil
+}
+
+// UpdateRecruit corrige os dados de um recruta. Os atributos informados substituem os anteriores
+func UpdateRecruit(recruit models.Recruit) error {
+	if _, err := GetRecruit(recruit.RecruitID); err != nil {
+		return err
+	}
+	if err := ValidateAttributes(recruit.Position, recruit.Archetype, recruit.Attributes); err != nil {
+		return err
+	}
+
+	_, err := database.DB.Exec(`
+        UPDATE recruits SET player_name = ?, class = ?, position = ?, tendency = ?, position_rank = ?, national_rank = ?,
+            stars = ?, hometown = ?, home_state = ?, height = ?, weight = ?, dev_trait = ?, overall = ?, gem_bust = ?,
+            recruitment_source = ?, recruitment_year = ?, team_id = ?, archetype = ?
+        WHERE recruit_id = ?
+    `, recruit.PlayerName, recruit.Class, recruit.Position, recruit.Tendency, recruit.PositionRank, recruit.NationalRank,
+		recruit.Stars, recruit.Hometown, recruit.HomeState, recruit.Height, recruit.Weight, recruit.DevTrait, recruit.Overall,
+		recruit.GemBust, recruit.RecruitmentSource, recruit.RecruitmentYear, recruit.TeamID, recruit.Archetype, recruit.RecruitID)
+	if err != nil {
+		fmt.Printf("Erro ao atualizar recruta: %v\n", err)
 		return err
 	}
+
+	if recruit.Attributes != nil {
+		return saveAttributes("recruit_attributes", "recruit_id", recruit.RecruitID, recruit.Attributes)
+	}
 	return nil
 }
+
+// ErrRecruitPromoted impede excluir um recruta que já virou jogador
+var ErrRecruitPromoted = errors.New("recruta já promovido a jogador; registre uma saída em vez de excluir")
+
+// DeleteRecruit exclui um recruta ainda não promovido a jogador. Se veio do quadro de
+// recrutamento, o alvo volta para a etapa committed
+func DeleteRecruit(id int) error {
+	if _, err := GetRecruit(id); err != nil {
+		return err
+	}
+	var promoted int
+	if err := database.DB.QueryRow("SELECT COUNT(*) FROM players WHERE recruit_id = ?", id).Scan(&promoted); err != nil {
+		return err
+	}
+	if promoted > 0 {
+		return ErrRecruitPromoted
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("UPDATE prospects SET recruit_id = NULL, stage = ? WHERE recruit_id = ?", StageCommitted, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM recruit_attributes WHERE recruit_id = ?", id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM recruits WHERE recruit_id = ?", id); err != nil {
+		fmt.Printf("Erro ao excluir recruta: %v\n", err)
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,326 @@
+package services
+
+import (
+	"dynastyTracker/database"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Grades da retrospectiva
+const (
+	GradeHit      = "hit"
+	GradeMiss     = "miss"
+	GradePending  = "pending"  // Carreira em andamento e ainda sem acerto
+	GradeUnlinked = "unlinked" // Recruta sem jogador vinculado (não promovido ou não chegou ao elenco)
+)
+
+// starExpectation é o que se espera da carreira de um recruta de cada faixa de estrelas. Atinge a
+// expectativa quem chega ao overall e às partidas como titular, é draftado até a rodada indicada
+// ou ganha/é finalista de um prêmio nacional ou All-American
+type starExpectation struct {
+	PeakOverall  int     `json:"peak_overall"`
+	GamesStarted int     `json:"games_started"`
+	DraftRound   int     `json:"draft_round"`
+	HitRate      float64 `json:"hit_rate"` // Taxa de acerto esperada, usada na nota da classe
+}
+
+var starExpectations = map[int]starExpectation{
+	5: {PeakOverall: 88, GamesStarted: 24, DraftRound: 2, HitRate: 0.80},
+	4: {PeakOverall: 84, GamesStarted: 16, DraftRound: 4, HitRate: 0.60},
+	3: {PeakOverall: 80, GamesStarted: 10, DraftRound: 7, HitRate: 0.40},
+	2: {PeakOverall: 76, GamesStarted: 4, DraftRound: 7, HitRate: 0.25},
+	1: {PeakOverall: 72, GamesStarted: 1, DraftRound: 7, HitRate: 0.15},
+}
+
+// Temporadas jogadas a partir das quais um recruta sem acerto deixa de ser "pending"
+const retrospectiveMinSeasons = 3
+
+func expectationForStars(stars int) starExpectation {
+	switch {
+	case stars > 5:
+		stars = 5
+	case stars < 1:
+		stars = 1
+	}
+	return starExpectations[stars]
+}
+
+type RecruitOutcome struct {
+	RecruitID       int      `json:"recruit_id"`
+	Name            string   `json:"name"`
+	Position        string   `json:"position"`
+	Stars           int      `json:"stars"`
+	GemBust         string   `json:"gem_bust"`
+	TeamID          int      `json:"team_id"`
+	Year            int      `json:"year"`
+	PlayerID        *int     `json:"player_id"`
+	Active          bool     `json:"active"`
+	Seasons         int      `json:"seasons"`
+	SigningOverall  int      `json:"signing_overall"`
+	PeakOverall     int      `json:"peak_overall"`
+	Growth          int      `json:"growth"`
+	GamesStarted    int      `json:"games_started"`
+	PassingYards    int      `json:"passing_yards"`
+	RushingYards    int      `json:"rushing_yards"`
+	ReceivingYards  int      `json:"receiving_yards"`
+	TotalTDs        int      `json:"total_tds"`
+	AwardsWon       int      `json:"awards_won"`
+	MajorHonors     int      `json:"major_honors"` // Prêmios nacionais e All-American (vencedor ou finalista)
+	DraftRound      *int     `json:"draft_round"`  // 0 = não draftado
+	DraftPick       *int     `json:"draft_overall_pick"`
+	Grade           string   `json:"grade"`
+	Outperformed    bool     `json:"outperformed"` // Atingiu a expectativa da faixa de estrelas acima
+	Reasons         []string `json:"reasons"`
+	LabelAssessment string   `json:"label_assessment,omitempty"` // correct, wrong ou pending para rótulos gem/bust
+}
+
+type ClassRetrospective struct {
+	TeamID       int     `json:"team_id"`
+	Year         int     `json:"year"`
+	Recruits     int     `json:"recruits"`
+	Graded       int     `json:"graded"`
+	Hits         int     `json:"hits"`
+	Misses       int     `json:"misses"`
+	Pending      int     `json:"pending"`
+	Unlinked     int     `json:"unlinked"`
+	HitRate      float64 `json:"hit_rate"`
+	ExpectedHits float64 `json:"expected_hits"` // Soma das taxas esperadas dos recrutas avaliados
+	Grade        string  `json:"grade"`         // hit quando os acertos alcançam o esperado
+}
+
+type GemBustAccuracy struct {
+	Label    string  `json:"label"` // gem, bust ou none (sem rótulo)
+	Recruits int     `json:"recruits"`
+	Graded   int     `json:"graded"`
+	Correct  int     `json:"correct"` // gem: superou a faixa; bust: miss; none: ficou dentro da faixa
+	Accuracy float64 `json:"accuracy"`
+}
+
+type RecruitingRetrospective struct {
+	Expectations map[int]starExpectation `json:"expectations"`
+	Recruits     []RecruitOutcome        `json:"recruits"`
+	Classes      []ClassRetrospective    `json:"classes"`
+	Labels       []GemBustAccuracy       `json:"labels"`
+}
+
+func meetsExpectation(outcome RecruitOutcome, expected starExpectation) []string {
+	var reasons []string
+	if outcome.PeakOverall >= expected.PeakOverall && outcome.GamesStarted >= expected.GamesStarted {
+		reasons = append(reasons, fmt.Sprintf("overall %d e %d jogos como titular", outcome.PeakOverall, outcome.GamesStarted))
+	}
+	if outcome.DraftRound != nil && *outcome.DraftRound > 0 && *outcome.DraftRound <= expected.DraftRound {
+		reasons = append(reasons, fmt.Sprintf("draftado na rodada %d", *outcome.DraftRound))
+	}
+	if outcome.MajorHonors > 0 {
+		reasons = append(reasons, fmt.Sprintf("%d prêmio(s) nacional(is) ou All-American", outcome.MajorHonors))
+	}
+	return reasons
+}
+
+// gradeRecruit classifica o recruta contra a expectativa das suas estrelas
+func gradeRecruit(outcome *RecruitOutcome, stillDeveloping bool) {
+	if outcome.PlayerID == nil {
+		outcome.Grade = GradeUnlinked
+		return
+	}
+
+	outcome.Reasons = meetsExpectation(*outcome, expectationForStars(outcome.Stars))
+	outcome.Outperformed = outcome.Stars < 5 && len(meetsExpectation(*outcome, expectationForStars(outcome.Stars+1))) > 0
+	switch {
+	case len(outcome.Reasons) > 0:
+		outcome.Grade = GradeHit
+	case stillDeveloping:
+		outcome.Grade = GradePending
+	default:
+		outcome.Grade = GradeMiss
+	}
+
+	switch strings.ToLower(outcome.GemBust) {
+	case "gem":
+		switch {
+		case outcome.Outperformed:
+			outcome.LabelAssessment = "correct"
+		case stillDeveloping:
+			outcome.LabelAssessment = GradePending
+		default:
+			outcome.LabelAssessment = "wrong"
+		}
+	case "bust":
+		switch outcome.Grade {
+		case GradeMiss:
+			outcome.LabelAssessment = "correct"
+		case GradePending:
+			outcome.LabelAssessment = GradePending
+		default:
+			outcome.LabelAssessment = "wrong"
+		}
+	}
+}
+
+// GetRecruitingRetrospective acompanha cada recruta na carreira de jogador (evolução do overall,
+// jogos como titular, estatísticas, prêmios e draft), classifica recrutas e classes como acerto
+// ou erro frente à expectativa das estrelas e mede a precisão dos rótulos gem/bust
+func GetRecruitingRetrospective(teamID int, year int) (RecruitingRetrospective, error) {
+	report := RecruitingRetrospective{Expectations: starExpectations}
+
+	query := `
+        SELECT r.recruit_id, r.player_name, r.position, r.stars, COALESCE(r.gem_bust, ''), r.team_id,
+            r.recruitment_year, r.overall, p.player_id, COALESCE(p.active, 0), COALESCE(p.eligibility_exhausted, 0),
+            GREATEST(COALESCE(p.overall, 0), COALESCE((SELECT MAX(pr.overall) FROM player_ratings pr WHERE pr.player_id = p.player_id), 0)),
+            COALESCE(p.games_started, 0),
+            (SELECT COUNT(DISTINCT s.year) FROM playergamestats g JOIN schedule s ON s.id = g.schedule_id WHERE g.player_id = p.player_id),
+            (SELECT COALESCE(SUM(g.passing_yards), 0) FROM playergamestats g WHERE g.player_id = p.player_id),
+            (SELECT COALESCE(SUM(g.rushing_yards), 0) FROM playergamestats g WHERE g.player_id = p.player_id),
+            (SELECT COALESCE(SUM(g.receiving_yards), 0) FROM playergamestats g WHERE g.player_id = p.player_id),
+            (SELECT COALESCE(SUM(COALESCE(g.passing_tds, 0) + COALESCE(g.rushing_tds, 0) + COALESCE(g.receiving_tds, 0)), 0)
+                FROM playergamestats g WHERE g.player_id = p.player_id),
+            (SELECT COUNT(*) FROM award_honors h WHERE h.player_id = p.player_id AND h.result = 'winner'),
+            (SELECT COUNT(*) FROM award_honors h JOIN awards a ON a.award_id = h.award_id
+                WHERE h.player_id = p.player_id AND a.category IN ('national', 'all_american')),
+            d.round, d.overall_pick
+        FROM recruits r
+        LEFT JOIN players p ON p.recruit_id = r.recruit_id
+        LEFT JOIN draft_declarations d ON d.player_id = p.player_id
+        WHERE 1=1`
+	var args []interface{}
+	if teamID > 0 {
+		query += " AND r.team_id = ?"
+		args = append(args, teamID)
+	}
+	if year > 0 {
+		query += " AND r.recruitment_year = ?"
+		args = append(args, year)
+	}
+	query += " ORDER BY r.recruitment_year, r.team_id, r.stars DESC, r.national_rank"
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		fmt.Printf("Erro ao executar consulta: %v\n", err)
+		return report, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var o RecruitOutcome
+		var exhausted bool
+		err := rows.Scan(&o.RecruitID, &o.Name, &o.Position, &o.Stars, &o.GemBust, &o.TeamID, &o.Year, &o.SigningOverall,
+			&o.PlayerID, &o.Active, &exhausted, &o.PeakOverall, &o.GamesStarted, &o.Seasons,
+			&o.PassingYards, &o.RushingYards, &o.ReceivingYards, &o.TotalTDs, &o.AwardsWon, &o.MajorHonors,
+			&o.DraftRound, &o.DraftPick)
+		if err != nil {
+			fmt.Printf("Erro ao escanear resultados: %v\n", err)
+			return report, err
+		}
+		if o.PlayerID != nil {
+			o.Growth = o.PeakOverall - o.SigningOverall
+		}
+		// Quem ainda está no elenco e jogou poucas temporadas não é contado como erro
+		stillDeveloping := o.Active && !exhausted && o.Seasons < retrospectiveMinSeasons
+		gradeRecruit(&o, stillDeveloping)
+		report.Recruits = append(report.Recruits, o)
+	}
+
+	report.Classes = summarizeClassRetrospectives(report.Recruits)
+	report.Labels = summarizeGemBustAccuracy(report.Recruits)
+	return report, nil
+}
+
+func summarizeClassRetrospectives(outcomes []RecruitOutcome) []ClassRetrospective {
+	type classKey struct{ teamID, year int }
+	classes := make(map[classKey]*ClassRetrospective)
+	var order []classKey
+
+	for _, o := range outcomes {
+		key := classKey{o.TeamID, o.Year}
+		class, ok := classes[key]
+		if !ok {
+			class = &ClassRetrospective{TeamID: o.TeamID, Year: o.Year}
+			classes[key] = class
+			order = append(order, key)
+		}
+		class.Recruits++
+		switch o.Grade {
+		case GradeHit:
+			class.Hits++
+		case GradeMiss:
+			class.Misses++
+		case GradePending:
+			class.Pending++
+		case GradeUnlinked:
+			class.Unlinked++
+		}
+		if o.Grade == GradeHit || o.Grade == GradeMiss {
+			class.Graded++
+			class.ExpectedHits += expectationForStars(o.Stars).HitRate
+		}
+	}
+
+	var result []ClassRetrospective
+	for _, key := range order {
+		class := classes[key]
+		switch {
+		case class.Graded == 0:
+			class.Grade = GradePending
+		case float64(class.Hits) >= class.ExpectedHits:
+			class.Grade = GradeHit
+		default:
+			class.Grade = GradeMiss
+		}
+		if class.Graded > 0 {
+			class.HitRate = float64(class.Hits) / float64(class.Graded)
+		}
+		result = append(result, *class)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Year != result[j].Year {
+			return result[i].Year < result[j].Year
+		}
+		return result[i].TeamID < result[j].TeamID
+	})
+	return result
+}
+
+// summarizeGemBustAccuracy mede os rótulos: gem acerta quando o recruta supera a faixa de estrelas,
+// bust acerta quando é miss e a ausência de rótulo acerta quando o recruta fica dentro da faixa
+func summarizeGemBustAccuracy(outcomes []RecruitOutcome) []GemBustAccuracy {
+	labels := map[string]*GemBustAccuracy{
+		"gem":  {Label: "gem"},
+		"bust": {Label: "bust"},
+		"none": {Label: "none"},
+	}
+	for _, o := range outcomes {
+		label := strings.ToLower(o.GemBust)
+		if label != "gem" && label != "bust" {
+			label = "none"
+		}
+		summary := labels[label]
+		summary.Recruits++
+
+		// Rótulos usam a avaliação de gradeRecruit; sem rótulo, acerta quem ficou dentro da faixa
+		var graded, correct bool
+		if label == "none" {
+			graded = o.Grade == GradeHit || o.Grade == GradeMiss
+			correct = o.Grade == GradeHit && !o.Outperformed
+		} else {
+			graded = o.LabelAssessment == "correct" || o.LabelAssessment == "wrong"
+			correct = o.LabelAssessment == "correct"
+		}
+		if graded {
+			summary.Graded++
+		}
+		if correct {
+			summary.Correct++
+		}
+	}
+
+	var result []GemBustAccuracy
+	for _, label := range []string{"gem", "bust", "none"} {
+		summary := labels[label]
+		if summary.Graded > 0 {
+			summary.Accuracy = float64(summary.Correct) / float64(summary.Graded)
+		}
+		result = append(result, *summary)
+	}
+	return result
+}
@@ -0,0 +1,333 @@
+package services
+
+import (
+	"database/sql"
+	"dynastyTracker/database"
+	"dynastyTracker/models"
+	"fmt"
+	"sort"
+)
+
+// GetProspectCompetitors lista as escolas que disputaram o alvo, da mais bem colocada para a menos
+func GetProspectCompetitors(prospectID int) ([]models.ProspectCompetitor, error) {
+	rows, err := database.DB.Query(`
+        SELECT c.prospect_id, c.team_id, t.school, c.standing, COALESCE(c.notes, '')
+        FROM prospect_competitors c
+        JOIN teams t ON t.team_id = c.team_id
+        WHERE c.prospect_id = ?
+        ORDER BY c.standing IS NULL, c.standing, t.school
+    `, prospectID)
+	if err != nil {
+		fmt.Printf("Erro ao buscar escolas concorrentes: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	competitors := []models.ProspectCompetitor{}
+	for rows.Next() {
+		var c models.ProspectCompetitor
+		if err := rows.Scan(&c.ProspectID, &c.TeamID, &c.School, &c.Standing, &c.Notes); err != nil {
+			fmt.Printf("Erro ao escanear resultados: %v\n", err)
+			return nil, err
+		}
+		competitors = append(competitors, c)
+	}
+	return competitors, nil
+}
+
+// SetProspectCompetitors substitui as escolas concorrentes do alvo. A escola com standing 1 é a
+// que o jogador escolheu, o que só vale para alvos que não assinaram nem se comprometeram com o time
+func SetProspectCompetitors(prospectID int, competitors []models.ProspectCompetitor) error {
+	prospect, err := GetProspect(prospectID)
+	if err == sql.ErrNoRows {
+		return ErrProspectNotFound
+	}
+	if err != nil {
+		return err
+	}
+	schools, err := getTeamSchools()
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[int]bool)
+	standings := make(map[int]bool)
+	for _, c := range competitors {
+		if _, ok := schools[c.TeamID]; !ok {
+			return fmt.Errorf("%w: time não encontrado: %d", ErrInvalidProspect, c.TeamID)
+		}
+		if c.TeamID == prospect.TeamID {
+			return fmt.Errorf("%w: o próprio time não é concorrente", ErrInvalidProspect)
+		}
+		if seen[c.TeamID] {
+			return fmt.Errorf("%w: escola repetida: %s", ErrInvalidProspect, schools[c.TeamID])
+		}
+		seen[c.TeamID] = true
+		if c.Standing == nil {
+			continue
+		}
+		if *c.Standing < 1 {
+			return fmt.Errorf("%w: posição final inválida para %s: %d", ErrInvalidProspect, schools[c.TeamID], *c.Standing)
+		}
+		if standings[*c.Standing] {
+			return fmt.Errorf("%w: posição final repetida: %d", ErrInvalidProspect, *c.Standing)
+		}
+		standings[*c.Standing] = true
+		if *c.Standing == 1 && (prospect.Stage == StageSigned || prospect.Stage == StageCommitted) {
+			return fmt.Errorf("%w: %s não pode ser a escola escolhida, o alvo está %s com o time", ErrProspectStage, schools[c.TeamID], prospect.Stage)
+		}
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM prospect_competitors WHERE prospect_id = ?", prospectID); err != nil {
+		return err
+	}
+	for _, c := range competitors {
+		_, err := tx.Exec("INSERT INTO prospect_competitors (prospect_id, team_id, standing, notes) VALUES (?, ?, ?, ?)",
+			prospectID, c.TeamID, c.Standing, c.Notes)
+		if err != nil {
+			fmt.Printf("Erro ao gravar escola concorrente: %v\n", err)
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+type RivalYear struct {
+	Year    int `json:"year"`
+	Battles int `json:"battles"`
+	Wins    int `json:"wins"`
+	Losses  int `json:"losses"`
+}
+
+// RivalRecord é o retrospecto do time nas disputas decididas contra uma escola
+type RivalRecord struct {
+	TeamID       int         `json:"team_id"`
+	School       string      `json:"school"`
+	Battles      int         `json:"battles"`
+	Wins         int         `json:"wins"`           // O alvo assinou com o time
+	Losses       int         `json:"losses"`         // O alvo escolheu esta escola
+	LostToOthers int         `json:"lost_to_others"` // O alvo escolheu outra escola (ou a escolha não foi registrada)
+	WinPct       float64     `json:"win_pct"`        // Vitórias sobre vitórias + derrotas diretas
+	Years        []RivalYear `json:"years"`
+}
+
+type SchoolLosses struct {
+	TeamID int    `json:"team_id"`
+	School string `json:"school"`
+	Losses int    `json:"losses"`
+}
+
+// BattleLosses agrupa os alvos perdidos para escolas conhecidas por posição ou por estado
+type BattleLosses struct {
+	Group   string         `json:"group"`
+	Losses  int            `json:"losses"`
+	Schools []SchoolLosses `json:"schools"` // Quem mais venceu primeiro
+}
+
+type RecruitingBattles struct {
+	TeamID           int            `json:"team_id"`
+	Decided          int            `json:"decided"` // Alvos assinados ou perdidos no período
+	Won              int            `json:"won"`
+	Lost             int            `json:"lost"`         // Perdidos para uma escola registrada
+	LostUnknown      int            `json:"lost_unknown"` // Perdidos sem escola escolhida registrada
+	Rivals           []RivalRecord  `json:"rivals"`
+	LossesByPosition []BattleLosses `json:"losses_by_position"`
+	LossesByState    []BattleLosses `json:"losses_by_state"`
+}
+
+type battle struct {
+	prospectID int
+	year       int
+	position   string
+	state      string
+	won        bool
+	winnerID   int // Escola escolhida quando o alvo foi perdido; 0 = não registrada
+}
+
+// GetRecruitingBattles monta o retrospecto das disputas de recrutamento do time contra cada escola
+// rival ao longo dos anos e mostra quem mais venceu o time por posição e por estado. Só contam alvos
+// decididos: assinados com o time ou perdidos
+func GetRecruitingBattles(teamID int, fromYear int, toYear int) (RecruitingBattles, error) {
+	report := RecruitingBattles{TeamID: teamID}
+
+	query := `
+        SELECT p.prospect_id, p.year, p.position, COALESCE(p.home_state, ''), p.stage,
+            COALESCE((SELECT c.team_id FROM prospect_competitors c WHERE c.prospect_id = p.prospect_id AND c.standing = 1), 0)
+        FROM prospects p
+        WHERE p.team_id = ? AND p.stage IN (?, ?)`
+	args := []interface{}{teamID, StageSigned, StageLost}
+	if fromYear > 0 {
+		query += " AND p.year >= ?"
+		args = append(args, fromYear)
+	}
+	if toYear > 0 {
+		query += " AND p.year <= ?"
+		args = append(args, toYear)
+	}
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		fmt.Printf("Erro ao executar consulta: %v\n", err)
+		return report, err
+	}
+	defer rows.Close()
+
+	battles := make(map[int]battle)
+	for rows.Next() {
+		var b battle
+		var stage string
+		if err := rows.Scan(&b.prospectID, &b.year, &b.position, &b.state, &stage, &b.winnerID); err != nil {
+			fmt.Printf("Erro ao escanear resultados: %v\n", err)
+			return report, err
+		}
+		b.state = normalizeState(b.state)
+		b.won = stage == StageSigned
+		if b.won {
+			b.winnerID = 0
+		}
+		battles[b.prospectID] = b
+
+		report.Decided++
+		switch {
+		case b.won:
+			report.Won++
+		case b.winnerID == 0:
+			report.LostUnknown++
+		default:
+			report.Lost++
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return report, err
+	}
+
+	schools, err := getTeamSchools()
+	if err != nil {
+		return report, err
+	}
+
+	// Cada escola listada no alvo disputou a decisão
+	competitorRows, err := database.DB.Query(`
+        SELECT c.prospect_id, c.team_id
+        FROM prospect_competitors c
+        JOIN prospects p ON p.prospect_id = c.prospect_id
+        WHERE p.team_id = ?
+    `, teamID)
+	if err != nil {
+		fmt.Printf("Erro ao buscar escolas concorrentes: %v\n", err)
+		return report, err
+	}
+	defer competitorRows.Close()
+
+	rivals := make(map[int]*RivalRecord)
+	rivalYears := make(map[int]map[int]*RivalYear)
+	for competitorRows.Next() {
+		var prospectID, rivalID int
+		if err := competitorRows.Scan(&prospectID, &rivalID); err != nil {
+			fmt.Printf("Erro ao escanear resultados: %v\n", err)
+			return report, err
+		}
+		b, ok := battles[prospectID]
+		if !ok {
+			continue
+		}
+
+		rival, ok := rivals[rivalID]
+		if !ok {
+			rival = &RivalRecord{TeamID: rivalID, School: schools[rivalID]}
+			rivals[rivalID] = rival
+			rivalYears[rivalID] = make(map[int]*RivalYear)
+		}
+		year, ok := rivalYears[rivalID][b.year]
+		if !ok {
+			year = &RivalYear{Year: b.year}
+			rivalYears[rivalID][b.year] = year
+		}
+
+		rival.Battles++
+		year.Battles++
+		switch {
+		case b.won:
+			rival.Wins++
+			year.Wins++
+		case b.winnerID == rivalID:
+			rival.Losses++
+			year.Losses++
+		default:
+			rival.LostToOthers++
+		}
+	}
+	if err := competitorRows.Err(); err != nil {
+		return report, err
+	}
+
+	for rivalID, rival := range rivals {
+		if rival.Wins+rival.Losses > 0 {
+			rival.WinPct = float64(rival.Wins) / float64(rival.Wins+rival.Losses)
+		}
+		for _, year := range rivalYears[rivalID] {
+			rival.Years = append(rival.Years, *year)
+		}
+		sort.Slice(rival.Years, func(i, j int) bool { return rival.Years[i].Year < rival.Years[j].Year })
+		report.Rivals = append(report.Rivals, *rival)
+	}
+	sort.Slice(report.Rivals, func(i, j int) bool {
+		if report.Rivals[i].Battles != report.Rivals[j].Battles {
+			return report.Rivals[i].Battles > report.Rivals[j].Battles
+		}
+		return report.Rivals[i].School < report.Rivals[j].School
+	})
+
+	byPosition := make(map[string]map[int]int)
+	byState := make(map[string]map[int]int)
+	for _, b := range battles {
+		if b.won || b.winnerID == 0 {
+			continue
+		}
+		countLoss(byPosition, b.position, b.winnerID)
+		if b.state != "" {
+			countLoss(byState, b.state, b.winnerID)
+		}
+	}
+	report.LossesByPosition = summarizeBattleLosses(byPosition, schools)
+	report.LossesByState = summarizeBattleLosses(byState, schools)
+	return report, nil
+}
+
+func countLoss(groups map[string]map[int]int, group string, winnerID int) {
+	if groups[group] == nil {
+		groups[group] = make(map[int]int)
+	}
+	groups[group][winnerID]++
+}
+
+func summarizeBattleLosses(groups map[string]map[int]int, schools map[int]string) []BattleLosses {
+	result := []BattleLosses{}
+	for group, winners := range groups {
+		losses := BattleLosses{Group: group}
+		for teamID, count := range winners {
+			losses.Losses += count
+			losses.Schools = append(losses.Schools, SchoolLosses{TeamID: teamID, School: schools[teamID], Losses: count})
+		}
+		sort.Slice(losses.Schools, func(i, j int) bool {
+			if losses.Schools[i].Losses != losses.Schools[j].Losses {
+				return losses.Schools[i].Losses > losses.Schools[j].Losses
+			}
+			return losses.Schools[i].School < losses.Schools[j].School
+		})
+		result = append(result, losses)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Losses != result[j].Losses {
+			return result[i].Losses > result[j].Losses
+		}
+		return result[i].Group < result[j].Group
+	})
+	return result
+}
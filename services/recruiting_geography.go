@@ -0,0 +1,288 @@
+package services
+
+import (
+	"dynastyTracker/database"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Peso de cada classe na força do pipeline: a classe mais recente vale 1, a anterior 0,8...
+const pipelineRecencyDecay = 0.8
+
+type GeographyFilter struct {
+	TeamID   int
+	FromYear int
+	ToYear   int
+	Source   string // "High School", "Transfer Portal" ou vazio para todos
+}
+
+type GeographyYear struct {
+	Year         int     `json:"year"`
+	Signees      int     `json:"signees"`
+	AverageStars float64 `json:"average_stars"`
+}
+
+type StatePipeline struct {
+	State        string          `json:"state"`
+	Signees      int             `json:"signees"`
+	AverageStars float64         `json:"average_stars"`
+	StarCounts   map[int]int     `json:"star_counts"`
+	Strength     float64         `json:"strength"` // Soma das estrelas com peso decrescente para classes antigas
+	Years        []GeographyYear `json:"years"`
+}
+
+type HometownSignees struct {
+	City         string          `json:"city"`
+	State        string          `json:"state"`
+	Signees      int             `json:"signees"`
+	AverageStars float64         `json:"average_stars"`
+	Recruits     []string        `json:"recruits"`
+	Years        []GeographyYear `json:"years"`
+	Longitude    *float64        `json:"longitude"`
+	Latitude     *float64        `json:"latitude"`
+	Precision    string          `json:"precision"` // city, state ou unknown (sem coordenadas)
+}
+
+type RecruitingGeography struct {
+	States    []StatePipeline   `json:"states"`
+	Hometowns []HometownSignees `json:"hometowns"`
+}
+
+type geoSignee struct {
+	name, city, state string
+	stars, year       int
+}
+
+// placeKey normaliza nomes de lugares para comparação: sem acentos (em qualquer caixa) e em maiúsculas
+func placeKey(name string) string {
+	return strings.ToUpper(strings.TrimSpace(accentReplacer.Replace(strings.ToLower(name))))
+}
+
+// normalizeState converte o estado para a sigla (aceita sigla ou nome completo)
+func normalizeState(state string) string {
+	state = placeKey(state)
+	if abbreviation, ok := stateNames[state]; ok {
+		return abbreviation
+	}
+	return state
+}
+
+// locateHometown busca as coordenadas da cidade e, se não houver, do estado
+func locateHometown(city string, state string) (*geoPoint, string) {
+	key := placeKey(city) + "|" + state
+	if point, ok := cityCentroids[key]; ok {
+		return &point, "city"
+	}
+	if point, ok := stateCentroids[state]; ok {
+		return &point, "state"
+	}
+	return nil, "unknown"
+}
+
+func loadGeoSignees(filter GeographyFilter) ([]geoSignee, error) {
+	query := `
+        SELECT player_name, COALESCE(hometown, ''), COALESCE(home_state, ''), stars, recruitment_year
+        FROM recruits WHERE 1=1`
+	var args []interface{}
+	if filter.TeamID > 0 {
+		query += " AND team_id = ?"
+		args = append(args, filter.TeamID)
+	}
+	if filter.FromYear > 0 {
+		query += " AND recruitment_year >= ?"
+		args = append(args, filter.FromYear)
+	}
+	if filter.ToYear > 0 {
+		query += " AND recruitment_year <= ?"
+		args = append(args, filter.ToYear)
+	}
+	if filter.Source != "" {
+		query += " AND recruitment_source = ?"
+		args = append(args, filter.Source)
+	}
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		fmt.Printf("Erro ao executar consulta: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var signees []geoSignee
+	for rows.Next() {
+		var s geoSignee
+		if err := rows.Scan(&s.name, &s.city, &s.state, &s.stars, &s.year); err != nil {
+			fmt.Printf("Erro ao escanear resultados: %v\n", err)
+			return nil, err
+		}
+		s.city = strings.TrimSpace(s.city)
+		s.state = normalizeState(s.state)
+		signees = append(signees, s)
+	}
+	return signees, nil
+}
+
+// summarizeYears agrupa os recrutas por ano, em ordem
+func summarizeYears(signees []geoSignee) []GeographyYear {
+	byYear := make(map[int]*GeographyYear)
+	for _, s := range signees {
+		year, ok := byYear[s.year]
+		if !ok {
+			year = &GeographyYear{Year: s.year}
+			byYear[s.year] = year
+		}
+		year.Signees++
+		year.AverageStars += float64(s.stars)
+	}
+	var years []GeographyYear
+	for _, year := range byYear {
+		year.AverageStars /= float64(year.Signees)
+		years = append(years, *year)
+	}
+	sort.Slice(years, func(i, j int) bool { return years[i].Year < years[j].Year })
+	return years
+}
+
+func averageStars(signees []geoSignee) float64 {
+	total := 0
+	for _, s := range signees {
+		total += s.stars
+	}
+	return float64(total) / float64(len(signees))
+}
+
+// GetRecruitingGeography resume os recrutas assinados por estado e cidade ao longo do tempo,
+// com a força do pipeline de cada estado
+func GetRecruitingGeography(filter GeographyFilter) (RecruitingGeography, error) {
+	var report RecruitingGeography
+
+	signees, err := loadGeoSignees(filter)
+	if err != nil {
+		return report, err
+	}
+	latestYear := 0
+	for _, s := range signees {
+		latestYear = max(latestYear, s.year)
+	}
+
+	byState := make(map[string][]geoSignee)
+	byHometown := make(map[string][]geoSignee)
+	for _, s := range signees {
+		if s.state == "" {
+			continue
+		}
+		byState[s.state] = append(byState[s.state], s)
+		if s.city != "" {
+			key := placeKey(s.city) + "|" + s.state
+			byHometown[key] = append(byHometown[key], s)
+		}
+	}
+
+	for state, group := range byState {
+		pipeline := StatePipeline{State: state, Signees: len(group), AverageStars: averageStars(group),
+			StarCounts: make(map[int]int), Years: summarizeYears(group)}
+		for _, s := range group {
+			pipeline.StarCounts[s.stars]++
+			pipeline.Strength += float64(s.stars) * math.Pow(pipelineRecencyDecay, float64(latestYear-s.year))
+		}
+		report.States = append(report.States, pipeline)
+	}
+	sort.Slice(report.States, func(i, j int) bool {
+		if report.States[i].Strength != report.States[j].Strength {
+			return report.States[i].Strength > report.States[j].Strength
+		}
+		return report.States[i].State < report.States[j].State
+	})
+
+	for _, group := range byHometown {
+		hometown := HometownSignees{City: group[0].city, State: group[0].state, Signees: len(group),
+			AverageStars: averageStars(group), Years: summarizeYears(group)}
+		for _, s := range group {
+			hometown.Recruits = append(hometown.Recruits, s.name)
+		}
+		point, precision := locateHometown(hometown.City, hometown.State)
+		hometown.Precision = precision
+		if point != nil {
+			hometown.Longitude, hometown.Latitude = &point.Lon, &point.Lat
+		}
+		report.Hometowns = append(report.Hometowns, hometown)
+	}
+	sort.Slice(report.Hometowns, func(i, j int) bool {
+		if report.Hometowns[i].Signees != report.Hometowns[j].Signees {
+			return report.Hometowns[i].Signees > report.Hometowns[j].Signees
+		}
+		if report.Hometowns[i].City != report.Hometowns[j].City {
+			return report.Hometowns[i].City < report.Hometowns[j].City
+		}
+		return report.Hometowns[i].State < report.Hometowns[j].State
+	})
+	return report, nil
+}
+
+type GeoJSONFeature struct {
+	Type       string                 `json:"type"`
+	Geometry   GeoJSONGeometry        `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type GeoJSONGeometry struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"` // [longitude, latitude]
+}
+
+type GeoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []GeoJSONFeature `json:"features"`
+}
+
+// GetRecruitingGeoJSON exporta o mapa de recrutamento como GeoJSON, com um ponto por cidade
+// ("city") ou por estado ("state"). Cidades sem coordenadas conhecidas usam o centro do estado
+func GetRecruitingGeoJSON(filter GeographyFilter, level string) (GeoJSONFeatureCollection, error) {
+	collection := GeoJSONFeatureCollection{Type: "FeatureCollection", Features: []GeoJSONFeature{}}
+
+	report, err := GetRecruitingGeography(filter)
+	if err != nil {
+		return collection, err
+	}
+
+	if level == "state" {
+		for _, state := range report.States {
+			point, ok := stateCentroids[state.State]
+			if !ok {
+				continue
+			}
+			collection.Features = append(collection.Features, GeoJSONFeature{
+				Type:     "Feature",
+				Geometry: GeoJSONGeometry{Type: "Point", Coordinates: [2]float64{point.Lon, point.Lat}},
+				Properties: map[string]interface{}{
+					"state":         state.State,
+					"signees":       state.Signees,
+					"average_stars": state.AverageStars,
+					"strength":      state.Strength,
+				},
+			})
+		}
+		return collection, nil
+	}
+
+	for _, hometown := range report.Hometowns {
+		if hometown.Longitude == nil {
+			continue
+		}
+		collection.Features = append(collection.Features, GeoJSONFeature{
+			Type:     "Feature",
+			Geometry: GeoJSONGeometry{Type: "Point", Coordinates: [2]float64{*hometown.Longitude, *hometown.Latitude}},
+			Properties: map[string]interface{}{
+				"city":          hometown.City,
+				"state":         hometown.State,
+				"signees":       hometown.Signees,
+				"average_stars": hometown.AverageStars,
+				"recruits":      hometown.Recruits,
+				"precision":     hometown.Precision,
+			},
+		})
+	}
+	return collection, nil
+}
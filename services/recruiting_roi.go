@@ -0,0 +1,274 @@
+package services
+
+import (
+	"dynastyTracker/database"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+type RecruitingROIFilter struct {
+	TeamID   int
+	FromYear int // Ano de recrutamento
+	ToYear   int
+	Position string
+}
+
+// Só há estatísticas ofensivas em playergamestats; jardas e TDs de scrimmage são comparados apenas
+// entre estas posições. Jardas e TDs de passe contam à parte, só para QBs
+var productionPositions = []string{"QB", "HB", "FB", "WR", "TE"}
+
+func isProductionPosition(position string) bool {
+	for _, p := range productionPositions {
+		if p == position {
+			return true
+		}
+	}
+	return false
+}
+
+// ROIVsRoster é a diferença entre o grupo e a média do elenco (positivo = acima da média). Nos grupos
+// por posição, a comparação é com a média do elenco na mesma posição
+type ROIVsRoster struct {
+	GamesStarted        float64 `json:"games_started"`
+	YardsPerGame        float64 `json:"yards_per_game"`
+	TouchdownsPerGame   float64 `json:"touchdowns_per_game"`
+	PassingYardsPerGame float64 `json:"passing_yards_per_game"`
+	OverallGrowth       float64 `json:"overall_growth"`
+	CurrentOverall      float64 `json:"current_overall"`
+}
+
+type ROIGroup struct {
+	Group               string      `json:"group"`
+	Players             int         `json:"players"`
+	ProductionPlayers   int         `json:"production_players"` // Jogadores de production_positions, os únicos com jardas e TDs
+	GamesPlayed         int         `json:"games_played"`       // Jogos com estatísticas dos production_players
+	GamesStarted        int         `json:"games_started"`
+	AvgGamesStarted     float64     `json:"avg_games_started"`
+	ScrimmageYards      int         `json:"scrimmage_yards"` // Corrida e recepção
+	Touchdowns          int         `json:"touchdowns"`      // TDs de scrimmage
+	YardsPerGame        float64     `json:"yards_per_game"`
+	TouchdownsPerGame   float64     `json:"touchdowns_per_game"`
+	PassingGames        int         `json:"passing_games"` // Jogos dos QBs do grupo
+	PassingYards        int         `json:"passing_yards"`
+	PassingTDs          int         `json:"passing_tds"`
+	PassingYardsPerGame float64     `json:"passing_yards_per_game"`
+	PassingTDsPerGame   float64     `json:"passing_tds_per_game"`
+	AvgSigningOverall   float64     `json:"avg_signing_overall"` // Overall do recrutamento ou primeiro rating
+	AvgCurrentOverall   float64     `json:"avg_current_overall"`
+	AvgOverallGrowth    float64     `json:"avg_overall_growth"`
+	AvgGrowthPerSeason  float64     `json:"avg_growth_per_season"`
+	VsRoster            ROIVsRoster `json:"vs_roster"`
+	signingOverallTotal int
+	currentOverallTotal int
+	growthPerSeason     float64
+}
+
+type ROIYear struct {
+	Year    int        `json:"year"`
+	All     ROIGroup   `json:"all"`
+	ByStars []ROIGroup `json:"by_stars"`
+}
+
+type RecruitingROIReport struct {
+	ProductionPositions []string   `json:"production_positions"` // Posições consideradas nas jardas e TDs
+	Roster              ROIGroup   `json:"roster"`               // Todos os jogadores do elenco filtrado, recrutados ou não
+	ByStars             []ROIGroup `json:"by_stars"`
+	BySource            []ROIGroup `json:"by_source"`
+	ByPosition          []ROIGroup `json:"by_position"`
+	ByYear              []ROIYear  `json:"by_year"`
+}
+
+type roiPlayer struct {
+	position                   string
+	stars                      *int
+	recruitmentYear            *int
+	source                     string
+	gamesStarted, gamesPlayed  int
+	yards, touchdowns, seasons int
+	passingYards, passingTDs   int
+	signingOverall, overall    int
+}
+
+func (g *ROIGroup) add(p roiPlayer) {
+	g.Players++
+	g.GamesStarted += p.gamesStarted
+	if isProductionPosition(p.position) {
+		g.ProductionPlayers++
+		g.GamesPlayed += p.gamesPlayed
+		g.ScrimmageYards += p.yards
+		g.Touchdowns += p.touchdowns
+	}
+	if p.position == "QB" {
+		g.PassingGames += p.gamesPlayed
+		g.PassingYards += p.passingYards
+		g.PassingTDs += p.passingTDs
+	}
+	g.signingOverallTotal += p.signingOverall
+	g.currentOverallTotal += p.overall
+	g.growthPerSeason += float64(p.overall-p.signingOverall) / float64(max(p.seasons-1, 1))
+}
+
+// finish calcula as médias do grupo e a comparação com o elenco
+func (g *ROIGroup) finish(roster *ROIGroup) {
+	if g.Players > 0 {
+		players := float64(g.Players)
+		g.AvgGamesStarted = float64(g.GamesStarted) / players
+		g.AvgSigningOverall = float64(g.signingOverallTotal) / players
+		g.AvgCurrentOverall = float64(g.currentOverallTotal) / players
+		g.AvgOverallGrowth = g.AvgCurrentOverall - g.AvgSigningOverall
+		g.AvgGrowthPerSeason = g.growthPerSeason / players
+	}
+	if g.GamesPlayed > 0 {
+		g.YardsPerGame = float64(g.ScrimmageYards) / float64(g.GamesPlayed)
+		g.TouchdownsPerGame = float64(g.Touchdowns) / float64(g.GamesPlayed)
+	}
+	if g.PassingGames > 0 {
+		g.PassingYardsPerGame = float64(g.PassingYards) / float64(g.PassingGames)
+		g.PassingTDsPerGame = float64(g.PassingTDs) / float64(g.PassingGames)
+	}
+	if roster != nil {
+		g.VsRoster = ROIVsRoster{
+			GamesStarted:        g.AvgGamesStarted - roster.AvgGamesStarted,
+			YardsPerGame:        g.YardsPerGame - roster.YardsPerGame,
+			TouchdownsPerGame:   g.TouchdownsPerGame - roster.TouchdownsPerGame,
+			PassingYardsPerGame: g.PassingYardsPerGame - roster.PassingYardsPerGame,
+			OverallGrowth:       g.AvgOverallGrowth - roster.AvgOverallGrowth,
+			CurrentOverall:      g.AvgCurrentOverall - roster.AvgCurrentOverall,
+		}
+	}
+}
+
+func starsGroup(stars int) string {
+	return strconv.Itoa(stars) + "-star"
+}
+
+// sortedGroups finaliza os grupos contra a média de referência de cada um e devolve em ordem pela chave informada
+func sortedGroups(groups map[string]*ROIGroup, baseline func(group string) *ROIGroup, less func(a, b string) bool) []ROIGroup {
+	result := []ROIGroup{}
+	for key, group := range groups {
+		group.finish(baseline(key))
+		result = append(result, *group)
+	}
+	sort.Slice(result, func(i, j int) bool { return less(result[i].Group, result[j].Group) })
+	return result
+}
+
+func groupIn(groups map[string]*ROIGroup, key string) *ROIGroup {
+	group, ok := groups[key]
+	if !ok {
+		group = &ROIGroup{Group: key}
+		groups[key] = group
+	}
+	return group
+}
+
+// GetRecruitingROIReport compara a produção em campo (playergamestats), os jogos como titular e a
+// evolução do overall dos jogadores recrutados, agrupados pelas estrelas do recrutamento, pela
+// origem (ensino médio ou portal) e pela posição, com resumo por ano de recrutamento. Cada grupo
+// traz a diferença para a média do elenco. Jardas e TDs de scrimmage só contam jogadores de
+// productionPositions; os de passe, só QBs
+func GetRecruitingROIReport(filter RecruitingROIFilter) (RecruitingROIReport, error) {
+	report := RecruitingROIReport{ProductionPositions: productionPositions, Roster: ROIGroup{Group: "roster"}}
+	rosterByPosition := make(map[string]*ROIGroup)
+
+	query := `
+        SELECT p.position, r.stars, r.recruitment_year, COALESCE(NULLIF(r.recruitment_source, ''), NULLIF(p.recruitment_source, ''), 'Unknown'),
+            p.games_started, COALESCE(production.games, 0), COALESCE(production.scrimmage_yards, 0), COALESCE(production.scrimmage_tds, 0),
+            COALESCE(production.passing_yards, 0), COALESCE(production.passing_tds, 0),
+            COALESCE(ratings.seasons, 1), COALESCE(r.overall, first_rating.overall, p.overall), p.overall
+        FROM players p
+        LEFT JOIN recruits r ON r.recruit_id = p.recruit_id
+        LEFT JOIN (
+            SELECT pr.player_id, pr.overall
+            FROM player_ratings pr
+            JOIN (SELECT player_id, MIN(year) AS first_year FROM player_ratings GROUP BY player_id) f
+              ON f.player_id = pr.player_id AND f.first_year = pr.year
+        ) first_rating ON first_rating.player_id = p.player_id
+        LEFT JOIN (
+            SELECT player_id, COUNT(*) AS seasons FROM player_ratings GROUP BY player_id
+        ) ratings ON ratings.player_id = p.player_id
+        LEFT JOIN ` + productionByPlayer + ` production ON production.player_id = p.player_id
+        WHERE 1=1`
+	var args []interface{}
+	if filter.TeamID > 0 {
+		query += " AND p.team_id = ?"
+		args = append(args, filter.TeamID)
+	}
+	if filter.Position != "" {
+		query += " AND p.position = ?"
+		args = append(args, filter.Position)
+	}
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		fmt.Printf("Erro ao executar consulta: %v\n", err)
+		return report, err
+	}
+	defer rows.Close()
+
+	var recruited []roiPlayer
+	for rows.Next() {
+		var p roiPlayer
+		err := rows.Scan(&p.position, &p.stars, &p.recruitmentYear, &p.source, &p.gamesStarted, &p.gamesPlayed,
+			&p.yards, &p.touchdowns, &p.passingYards, &p.passingTDs, &p.seasons, &p.signingOverall, &p.overall)
+		if err != nil {
+			fmt.Printf("Erro ao escanear resultados: %v\n", err)
+			return report, err
+		}
+		// A média do elenco inclui walk-ons e jogadores cadastrados sem recrutamento
+		report.Roster.add(p)
+		groupIn(rosterByPosition, p.position).add(p)
+
+		if p.stars == nil || p.recruitmentYear == nil {
+			continue
+		}
+		if (filter.FromYear > 0 && *p.recruitmentYear < filter.FromYear) || (filter.ToYear > 0 && *p.recruitmentYear > filter.ToYear) {
+			continue
+		}
+		recruited = append(recruited, p)
+	}
+	report.Roster.finish(nil)
+	for _, group := range rosterByPosition {
+		group.finish(nil)
+	}
+
+	byStars := make(map[string]*ROIGroup)
+	bySource := make(map[string]*ROIGroup)
+	byPosition := make(map[string]*ROIGroup)
+	byYear := make(map[int]*ROIYear)
+	byYearStars := make(map[int]map[string]*ROIGroup)
+	for _, p := range recruited {
+		groupIn(byStars, starsGroup(*p.stars)).add(p)
+		groupIn(bySource, p.source).add(p)
+		groupIn(byPosition, p.position).add(p)
+
+		year, ok := byYear[*p.recruitmentYear]
+		if !ok {
+			year = &ROIYear{Year: *p.recruitmentYear, All: ROIGroup{Group: "all"}}
+			byYear[*p.recruitmentYear] = year
+			byYearStars[*p.recruitmentYear] = make(map[string]*ROIGroup)
+		}
+		year.All.add(p)
+		groupIn(byYearStars[*p.recruitmentYear], starsGroup(*p.stars)).add(p)
+	}
+
+	// Mais estrelas primeiro; as demais chaves em ordem alfabética
+	starsOrder := func(a, b string) bool { return a > b }
+	alphabetical := func(a, b string) bool { return a < b }
+
+	wholeRoster := func(string) *ROIGroup { return &report.Roster }
+	samePosition := func(position string) *ROIGroup { return rosterByPosition[position] }
+
+	report.ByStars = sortedGroups(byStars, wholeRoster, starsOrder)
+	report.BySource = sortedGroups(bySource, wholeRoster, alphabetical)
+	report.ByPosition = sortedGroups(byPosition, samePosition, alphabetical)
+	report.ByYear = []ROIYear{}
+	for yearNumber, year := range byYear {
+		year.All.finish(&report.Roster)
+		year.ByStars = sortedGroups(byYearStars[yearNumber], wholeRoster, starsOrder)
+		report.ByYear = append(report.ByYear, *year)
+	}
+	sort.Slice(report.ByYear, func(i, j int) bool { return report.ByYear[i].Year < report.ByYear[j].Year })
+	return report, nil
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"dynastyTracker/database"
 	"fmt"
+	"sort"
 )
 
 // Estrutura para representar o relatório de desempenho do time
@@ -180,11 +181,11 @@ func GetComparisonWithHistoricalRecords() ([]ComparisonReport, error) {
 	query := `
         SELECT h.player_name AS historical_player, h.year_start, h.year_end, 
                COALESCE(h.completions, 0) AS historical_completions,
-               COALESCE(c.name, 'N/A') AS current_player, COALESCE(SUM(g.completions), 0) AS current_completions
+               COALESCE(MAX(c.name), 'N/A') AS current_player, COALESCE(SUM(g.completions), 0) AS current_completions
         FROM historicalrecords h
-        LEFT JOIN players c ON c.name = h.player_name
+        LEFT JOIN players c ON c.person_id = h.person_id
         LEFT JOIN playergamestats g ON g.player_id = c.player_id
-        GROUP BY h.player_name, h.year_start, h.year_end, h.completions, c.name
+        GROUP BY h.record_id, h.player_name, h.year_start, h.year_end, h.completions
         ORDER BY current_completions DESC;
     `
 
@@ -235,7 +236,7 @@ type PlayerStatsReport struct {
 
 func GetPlayerStatsByPosition(position string) ([]PlayerStatsReport, error) {
 	query := `
-        SELECT name, position,
+        SELECT MAX(p.name) AS name, p.position,
             SUM(passing_yards) AS passing_yards,
             SUM(passing_tds) AS passing_tds,
             SUM(interceptions) AS interceptions,
@@ -255,10 +256,10 @@ func GetPlayerStatsByPosition(position string) ([]PlayerStatsReport, error) {
             COALESCE((SUM(rushing_yards) + SUM(receiving_yards)), 0) AS scrimmage_yards,
             COALESCE((SUM(rushing_yards) + SUM(receiving_yards)) / NULLIF((SUM(rush_attempts) + SUM(receptions)), 0), 0) AS yards_per_scrimmage,
             COALESCE((SUM(receiving_yards) / NULLIF(SUM(receptions), 0)), 0) AS yards_per_reception
-        FROM players
-        JOIN playergamestats ON players.player_id = playergamestats.player_id
-        WHERE position = ?
-        GROUP BY name, position
+        FROM players p
+        JOIN playergamestats ON p.player_id = playergamestats.player_id
+        WHERE p.position = ?
+        GROUP BY ` + personKey + `, p.position
         ORDER BY name;
     `
 
@@ -378,6 +379,10 @@ func PredictRecordBreak(playerID int, seasonsRemaining int) (PredictionReport, e
 	return prediction, nil
 }
 
+// personKey agrupa os registros de um mesmo atleta pelo person_id. Jogadores ainda sem pessoa
+// (anteriores a BackfillPeople) ficam separados pelo próprio player_id
+const personKey = "COALESCE(p.person_id, -p.player_id)"
+
 type CareerRecords struct {
 	MaxCompletions    int `json:"max_completions"`
 	MaxPassingYards   int `json:"max_passing_yards"`
@@ -391,13 +396,13 @@ type CareerRecords struct {
 func GetCareerRecords() (CareerRecords, error) {
 	query := `
         SELECT 
-            MAX(completions) AS max_completions,
-            MAX(passing_yards) AS max_passing_yards,
-            MAX(touchdowns) AS max_passing_tds,
-            MAX(rush_yards) AS max_rushing_yards,
-            MAX(rush_tds) AS max_rushing_tds,
-            MAX(receiving_yards) AS max_receiving_yards,
-            MAX(receiving_tds) AS max_receiving_tds
+            COALESCE(MAX(completions), 0) AS max_completions,
+            COALESCE(MAX(passing_yards), 0) AS max_passing_yards,
+            COALESCE(MAX(touchdowns), 0) AS max_passing_tds,
+            COALESCE(MAX(rush_yards), 0) AS max_rushing_yards,
+            COALESCE(MAX(rush_tds), 0) AS max_rushing_tds,
+            COALESCE(MAX(receiving_yards), 0) AS max_receiving_yards,
+            COALESCE(MAX(receiving_tds), 0) AS max_receiving_tds
         FROM historicalrecords;
     `
 
@@ -420,7 +425,9 @@ func GetCareerRecords() (CareerRecords, error) {
 }
 
 type PlayerCareerStats struct {
+	PersonID             *int   `json:"person_id"`
 	PlayerName           string `json:"player_name"`
+	Active               bool   `json:"active"` // false = jogador que já saiu do elenco
 	CareerCompletions    int    `json:"career_completions"`
 	CareerPassingYards   int    `json:"career_passing_yards"`
 	CareerPassingTDs     int    `json:"career_passing_tds"`
@@ -433,7 +440,9 @@ type PlayerCareerStats struct {
 func GetCurrentPlayerCareerStats() ([]PlayerCareerStats, error) {
 	query := `
         SELECT 
-            p.name AS player_name,
+            MAX(p.person_id) AS person_id,
+            MAX(p.name) AS player_name,
+            MAX(p.active) AS active,
             SUM(g.completions) AS career_completions,
             SUM(g.passing_yards) AS career_passing_yards,
             SUM(g.passing_tds) AS career_passing_tds,
@@ -443,7 +452,7 @@ func GetCurrentPlayerCareerStats() ([]PlayerCareerStats, error) {
             SUM(g.receiving_tds) AS career_receiving_tds
         FROM players p
         LEFT JOIN playergamestats g ON g.player_id = p.player_id
-        GROUP BY p.name
+        GROUP BY ` + personKey + `
         ORDER BY career_passing_yards DESC;
     `
 
@@ -458,7 +467,9 @@ func GetCurrentPlayerCareerStats() ([]PlayerCareerStats, error) {
 	for rows.Next() {
 		var stats PlayerCareerStats
 		err := rows.Scan(
+			&stats.PersonID,
 			&stats.PlayerName,
+			&stats.Active,
 			&stats.CareerCompletions,
 			&stats.CareerPassingYards,
 			&stats.CareerPassingTDs,
@@ -479,6 +490,7 @@ func GetCurrentPlayerCareerStats() ([]PlayerCareerStats, error) {
 
 type ComparisonWithRecord struct {
 	PlayerName           string `json:"player_name"`
+	Active               bool   `json:"active"`
 	CareerCompletions    int    `json:"career_completions"`
 	RecordCompletions    int    `json:"record_completions"`
 	CareerPassingYards   int    `json:"career_passing_yards"`
@@ -506,6 +518,7 @@ func ComparePlayerStatsWithRecords() ([]ComparisonWithRecord, error) {
 	for _, stats := range playerStats {
 		comparison := ComparisonWithRecord{
 			PlayerName:           stats.PlayerName,
+			Active:               stats.Active,
 			CareerCompletions:    stats.CareerCompletions,
 			RecordCompletions:    records.MaxCompletions,
 			CareerPassingYards:   stats.CareerPassingYards,
@@ -524,6 +537,7 @@ func ComparePlayerStatsWithRecords() ([]ComparisonWithRecord, error) {
 
 type PlayerYearlyStats struct {
 	Year           int `json:"year"`
+	Games          int `json:"games"` // Jogos com estatísticas; 0 = temporada só com prêmios
 	Completions    int `json:"completions"`
 	PassingYards   int `json:"passing_yards"`
 	PassingTDs     int `json:"passing_tds"`
@@ -531,12 +545,15 @@ type PlayerYearlyStats struct {
 	RushingTDs     int `json:"rushing_tds"`
 	ReceivingYards int `json:"receiving_yards"`
 	ReceivingTDs   int `json:"receiving_tds"`
+	AwardsWon      int `json:"awards_won"`
+	AwardFinalists int `json:"award_finalists"`
 }
 
 func GetPlayerCareerProgression(playerID int) ([]PlayerYearlyStats, error) {
 	query := `
         SELECT 
             s.year,  -- Obtém o ano da tabela schedule
+            COUNT(*) AS games,
             SUM(g.completions) AS completions,
             SUM(g.passing_yards) AS passing_yards,
             SUM(g.passing_tds) AS passing_tds,
@@ -563,6 +580,7 @@ func GetPlayerCareerProgression(playerID int) ([]PlayerYearlyStats, error) {
 		var stats PlayerYearlyStats
 		err := rows.Scan(
 			&stats.Year,
+			&stats.Games,
 			&stats.Completions,
 			&stats.PassingYards,
 			&stats.PassingTDs,
@@ -578,6 +596,22 @@ func GetPlayerCareerProgression(playerID int) ([]PlayerYearlyStats, error) {
 		yearlyStats = append(yearlyStats, stats)
 	}
 
+	// Inclui os prêmios de cada temporada, mesmo nas que não têm estatísticas
+	awardCounts, err := getPlayerAwardCountsByYear(playerID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range yearlyStats {
+		if counts, ok := awardCounts[yearlyStats[i].Year]; ok {
+			yearlyStats[i].AwardsWon, yearlyStats[i].AwardFinalists = counts[0], counts[1]
+			delete(awardCounts, yearlyStats[i].Year)
+		}
+	}
+	for year, counts := range awardCounts {
+		yearlyStats = append(yearlyStats, PlayerYearlyStats{Year: year, AwardsWon: counts[0], AwardFinalists: counts[1]})
+	}
+	sort.Slice(yearlyStats, func(i, j int) bool { return yearlyStats[i].Year < yearlyStats[j].Year })
+
 	return yearlyStats, nil
 }
 
@@ -589,13 +623,13 @@ type TopPlayerStats struct {
 func GetTopPlayersBySeason(year int, category string) ([]TopPlayerStats, error) {
 	query := fmt.Sprintf(`
         SELECT 
-            p.name AS player_name,
+            MAX(p.name) AS player_name,
             SUM(g.%s) AS stat_value
         FROM players p
         LEFT JOIN playergamestats g ON g.player_id = p.player_id
         JOIN schedule s ON g.schedule_id = s.id  -- Obtém o ano de schedule
         WHERE s.year = ?  -- Filtra pelo ano na tabela schedule
-        GROUP BY p.name
+        GROUP BY `+personKey+`
         ORDER BY stat_value DESC
         LIMIT 10;
     `, category)
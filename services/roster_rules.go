@@ -0,0 +1,493 @@
+package services
+
+import (
+	"database/sql"
+	"dynastyTracker/database"
+	"dynastyTracker/models"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Limite de bolsas usado enquanto a dynasty não configura suas regras
+const defaultScholarshipCap = 85
+
+// Tipos de regra de elenco
+const (
+	RuleScholarshipCap = "scholarship_cap"
+	RuleRosterCap      = "roster_cap"
+	RulePositionMin    = "position_min"
+	RulePositionMax    = "position_max"
+	RuleClassMax       = "class_max"
+)
+
+type RosterCounts struct {
+	Total       int                      `json:"total"`
+	Scholarship int                      `json:"scholarship"`
+	ByPosition  map[string]int           `json:"by_position"`
+	ByClass     map[models.ClassYear]int `json:"by_class"`
+}
+
+type RosterViolation struct {
+	Rule      string           `json:"rule"`
+	Position  string           `json:"position,omitempty"`
+	ClassYear models.ClassYear `json:"class_year,omitempty"`
+	Limit     int              `json:"limit"`
+	Count     int              `json:"count"`
+	Message   string           `json:"message"`
+}
+
+type RosterCompliance struct {
+	TeamID        int                `json:"team_id"`
+	Year          int                `json:"year"`
+	Rules         models.RosterRules `json:"rules"`
+	Current       RosterCounts       `json:"current"`
+	Violations    []RosterViolation  `json:"violations"`
+	Projected     RosterCounts       `json:"projected"`      // Elenco após a virada da temporada
+	AfterRollover []RosterViolation  `json:"after_rollover"` // Violações previstas para o elenco projetado
+
+	InvalidClassYears []InvalidClassYear `json:"invalid_class_years"` // Fora da projeção
+}
+
+// RosterRuleError indica que a alteração foi recusada por violar as regras de elenco
+type RosterRuleError struct {
+	Violations []string
+}
+
+func (e *RosterRuleError) Error() string {
+	return "regras de elenco violadas: " + strings.Join(e.Violations, "; ")
+}
+
+// sqlQueryer é implementado tanto por *sql.DB quanto por *sql.Tx
+type sqlQueryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func newRosterCounts() RosterCounts {
+	return RosterCounts{ByPosition: make(map[string]int), ByClass: make(map[models.ClassYear]int)}
+}
+
+func (counts *RosterCounts) add(position string, classYear models.ClassYear, walkOn bool, n int) {
+	counts.Total += n
+	if !walkOn {
+		counts.Scholarship += n
+	}
+	counts.ByPosition[position] += n
+	counts.ByClass[classYear] += n
+}
+
+// GetRosterRules obtém as regras de elenco do time. Sem configuração, vale o limite padrão de bolsas
+func GetRosterRules(teamID int) (models.RosterRules, error) {
+	return getRosterRules(database.DB, teamID)
+}
+
+// getRosterRules lê as regras pela conexão ou pela transação informada
+func getRosterRules(q sqlRunner, teamID int) (models.RosterRules, error) {
+	rules := models.RosterRules{TeamID: teamID, ScholarshipCap: defaultScholarshipCap}
+
+	err := q.QueryRow("SELECT scholarship_cap, roster_cap FROM roster_rules WHERE team_id = ?", teamID).
+		Scan(&rules.ScholarshipCap, &rules.RosterCap)
+	if err != nil && err != sql.ErrNoRows {
+		fmt.Printf("Erro ao buscar regras de elenco: %v\n", err)
+		return rules, err
+	}
+
+	rows, err := q.Query(`
+        SELECT position, min_players, max_players FROM roster_position_limits
+        WHERE team_id = ? ORDER BY position
+    `, teamID)
+	if err != nil {
+		fmt.Printf("Erro ao buscar limites por posição: %v\n", err)
+		return rules, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var limit models.PositionLimit
+		if err := rows.Scan(&limit.Position, &limit.Min, &limit.Max); err != nil {
+			return rules, err
+		}
+		rules.PositionLimits = append(rules.PositionLimits, limit)
+	}
+
+	classRows, err := q.Query("SELECT class_year, max_players FROM roster_class_limits WHERE team_id = ?", teamID)
+	if err != nil {
+		fmt.Printf("Erro ao buscar limites por ano acadêmico: %v\n", err)
+		return rules, err
+	}
+	defer classRows.Close()
+	for classRows.Next() {
+		var limit models.ClassLimit
+		if err := classRows.Scan(&limit.ClassYear, &limit.Max); err != nil {
+			return rules, err
+		}
+		rules.ClassLimits = append(rules.ClassLimits, limit)
+	}
+	return rules, nil
+}
+
+// SaveRosterRules substitui as regras de elenco do time
+func SaveRosterRules(rules models.RosterRules) error {
+	if rules.ScholarshipCap <= 0 {
+		return fmt.Errorf("o limite de bolsas deve ser maior que zero")
+	}
+	if rules.RosterCap > 0 && rules.RosterCap < rules.ScholarshipCap {
+		return fmt.Errorf("o limite do elenco (%d) não pode ser menor que o de bolsas (%d)", rules.RosterCap, rules.ScholarshipCap)
+	}
+	for _, limit := range rules.PositionLimits {
+		if limit.Max > 0 && limit.Min > limit.Max {
+			return fmt.Errorf("mínimo maior que o máximo para a posição %s", limit.Position)
+		}
+	}
+	for i, limit := range rules.ClassLimits {
+		classYear, err := ParseClassYear(string(limit.ClassYear))
+		if err != nil {
+			return err
+		}
+		rules.ClassLimits[i].ClassYear = classYear
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+        INSERT INTO roster_rules (team_id, scholarship_cap, roster_cap) VALUES (?, ?, ?)
+        ON DUPLICATE KEY UPDATE scholarship_cap = VALUES(scholarship_cap), roster_cap = VALUES(roster_cap)
+    `, rules.TeamID, rules.ScholarshipCap, rules.RosterCap)
+	if err != nil {
+		fmt.Printf("Erro ao gravar regras de elenco: %v\n", err)
+		return err
+	}
+
+	if _, err = tx.Exec("DELETE FROM roster_position_limits WHERE team_id = ?", rules.TeamID); err != nil {
+		return err
+	}
+	for _, limit := range rules.PositionLimits {
+		_, err = tx.Exec("INSERT INTO roster_position_limits (team_id, position, min_players, max_players) VALUES (?, ?, ?, ?)",
+			rules.TeamID, limit.Position, limit.Min, limit.Max)
+		if err != nil {
+			fmt.Printf("Erro ao gravar limite por posição: %v\n", err)
+			return err
+		}
+	}
+
+	if _, err = tx.Exec("DELETE FROM roster_class_limits WHERE team_id = ?", rules.TeamID); err != nil {
+		return err
+	}
+	for _, limit := range rules.ClassLimits {
+		_, err = tx.Exec("INSERT INTO roster_class_limits (team_id, class_year, max_players) VALUES (?, ?, ?)",
+			rules.TeamID, limit.ClassYear, limit.Max)
+		if err != nil {
+			fmt.Printf("Erro ao gravar limite por ano acadêmico: %v\n", err)
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// countRoster conta o elenco ativo do time
+func countRoster(q sqlQueryer, teamID int) (RosterCounts, error) {
+	counts := newRosterCounts()
+	rows, err := q.Query(`
+        SELECT position, class_year, walk_on, COUNT(*) FROM players
+        WHERE team_id = ? AND active = 1 AND eligibility_exhausted = 0
+        GROUP BY position, class_year, walk_on
+    `, teamID)
+	if err != nil {
+		fmt.Printf("Erro ao contar elenco: %v\n", err)
+		return counts, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var position string
+		var classYear models.ClassYear
+		var walkOn bool
+		var n int
+		if err := rows.Scan(&position, &classYear, &walkOn, &n); err != nil {
+			return counts, err
+		}
+		counts.add(position, classYear, walkOn, n)
+	}
+	return counts, rows.Err()
+}
+
+// checkRosterRules lista todas as regras descumpridas pelo elenco
+func checkRosterRules(rules models.RosterRules, counts RosterCounts) []RosterViolation {
+	var violations []RosterViolation
+
+	if counts.Scholarship > rules.ScholarshipCap {
+		violations = append(violations, RosterViolation{Rule: RuleScholarshipCap, Limit: rules.ScholarshipCap, Count: counts.Scholarship,
+			Message: fmt.Sprintf("%d bolsistas para um limite de %d", counts.Scholarship, rules.ScholarshipCap)})
+	}
+	if rules.RosterCap > 0 && counts.Total > rules.RosterCap {
+		violations = append(violations, RosterViolation{Rule: RuleRosterCap, Limit: rules.RosterCap, Count: counts.Total,
+			Message: fmt.Sprintf("%d jogadores no elenco para um limite de %d", counts.Total, rules.RosterCap)})
+	}
+	for _, limit := range rules.PositionLimits {
+		count := counts.ByPosition[limit.Position]
+		if limit.Max > 0 && count > limit.Max {
+			violations = append(violations, RosterViolation{Rule: RulePositionMax, Position: limit.Position, Limit: limit.Max, Count: count,
+				Message: fmt.Sprintf("%d jogadores de %s para um máximo de %d", count, limit.Position, limit.Max)})
+		}
+		if count < limit.Min {
+			violations = append(violations, RosterViolation{Rule: RulePositionMin, Position: limit.Position, Limit: limit.Min, Count: count,
+				Message: fmt.Sprintf("%d jogadores de %s para um mínimo de %d", count, limit.Position, limit.Min)})
+		}
+	}
+	for _, limit := range rules.ClassLimits {
+		count := counts.ByClass[limit.ClassYear]
+		if count > limit.Max {
+			violations = append(violations, RosterViolation{Rule: RuleClassMax, ClassYear: limit.ClassYear, Limit: limit.Max, Count: count,
+				Message: fmt.Sprintf("%d jogadores %s para um máximo de %d", count, limit.ClassYear, limit.Max)})
+		}
+	}
+	return violations
+}
+
+// violationCount devolve a contagem do elenco usada pela regra da violação
+func violationCount(counts RosterCounts, violation RosterViolation) int {
+	switch violation.Rule {
+	case RuleScholarshipCap:
+		return counts.Scholarship
+	case RuleRosterCap:
+		return counts.Total
+	case RulePositionMin, RulePositionMax:
+		return counts.ByPosition[violation.Position]
+	case RuleClassMax:
+		return counts.ByClass[violation.ClassYear]
+	}
+	return 0
+}
+
+// withRosterRules executa uma alteração de elenco dentro de uma transação que trava os times
+// envolvidos e aplica as regras. A alteração é desfeita se piorar algum limite máximo
+// (bolsas, elenco, posição ou ano acadêmico). Mínimos por posição só aparecem no relatório
+// de conformidade, pois um elenco em formação ainda não os atinge
+func withRosterRules(teamIDs []int, mutate func(tx *sql.Tx) error) error {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := applyRosterRules(tx, teamIDs, mutate); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// applyRosterRules faz o mesmo que withRosterRules dentro de uma transação já aberta. Se a
+// alteração falhar ou violar as regras, só ela é desfeita (savepoint) e a transação segue
+// utilizável, o que permite pular um item e continuar uma operação maior
+func applyRosterRules(tx *sql.Tx, teamIDs []int, mutate func(tx *sql.Tx) error) (err error) {
+	teams := make(map[int]bool)
+	var ordered []int
+	for _, teamID := range teamIDs {
+		if teamID > 0 && !teams[teamID] {
+			teams[teamID] = true
+			ordered = append(ordered, teamID)
+		}
+	}
+	// Travar sempre na mesma ordem evita deadlock entre alterações concorrentes
+	sort.Ints(ordered)
+
+	if _, err := tx.Exec("SAVEPOINT roster_rules"); err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if _, rollbackErr := tx.Exec("ROLLBACK TO SAVEPOINT roster_rules"); rollbackErr != nil {
+				fmt.Printf("Erro ao desfazer alteração de elenco: %v\n", rollbackErr)
+			}
+		}
+	}()
+
+	before := make(map[int]RosterCounts)
+	for _, teamID := range ordered {
+		var locked int
+		err := tx.QueryRow("SELECT team_id FROM teams WHERE team_id = ? FOR UPDATE", teamID).Scan(&locked)
+		if err != nil {
+			return fmt.Errorf("time não encontrado: %d", teamID)
+		}
+		if before[teamID], err = countRoster(tx, teamID); err != nil {
+			return err
+		}
+	}
+
+	if err := mutate(tx); err != nil {
+		return err
+	}
+
+	var problems []string
+	for _, teamID := range ordered {
+		rules, err := getRosterRules(tx, teamID)
+		if err != nil {
+			return err
+		}
+		after, err := countRoster(tx, teamID)
+		if err != nil {
+			return err
+		}
+		for _, violation := range checkRosterRules(rules, after) {
+			if violation.Rule == RulePositionMin {
+				continue
+			}
+			if violation.Count > violationCount(before[teamID], violation) {
+				problems = append(problems, violation.Message)
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return &RosterRuleError{Violations: problems}
+	}
+	return nil
+}
+
+// Destino de cada jogador do elenco na projeção da virada da temporada
+const (
+	projectionStays      = "stays"
+	projectionDeclared   = "declared"
+	projectionGraduating = "graduating"
+	projectionInPortal   = "in_portal"
+)
+
+// InvalidClassYear é um jogador deixado de fora por ter um ano acadêmico que não pôde ser lido
+type InvalidClassYear struct {
+	PlayerID  int    `json:"player_id"`
+	Name      string `json:"name"`
+	ClassYear string `json:"class_year"`
+}
+
+type projectedPlayer struct {
+	position  string
+	classYear models.ClassYear // Ano atual
+	newClass  models.ClassYear // Ano após a virada
+	walkOn    bool
+	overall   int
+	outcome   string
+}
+
+type rosterProjection struct {
+	players  []projectedPlayer
+	recruits []projectedPlayer // Recrutas assinados para a temporada seguinte, ainda não promovidos
+	invalid  []InvalidClassYear
+}
+
+// projectRollover projeta o elenco do time após a virada da temporada: quem se declarou ao draft,
+// esgota a elegibilidade ou está no portal sai; os demais avançam o ano acadêmico e os recrutas
+// assinados chegam. Jogadores com ano acadêmico ilegível ficam em invalid, fora da projeção.
+// Redshirts perdidos por excesso de jogos não são previstos
+func projectRollover(teamID int, year int) (rosterProjection, error) {
+	var projection rosterProjection
+
+	rows, err := database.DB.Query(`
+        SELECT p.player_id, p.name, p.position, p.class_year, p.redshirt_year, p.overall, p.walk_on,
+            EXISTS (SELECT 1 FROM draft_declarations d WHERE d.player_id = p.player_id AND d.year = ?) AS declared,
+            EXISTS (SELECT 1 FROM portal_transfers t WHERE t.player_id = p.player_id AND t.direction = ? AND t.status = ?) AS in_portal
+        FROM players p
+        WHERE p.team_id = ? AND p.active = 1 AND p.eligibility_exhausted = 0
+    `, year, TransferOutgoing, PortalInPortal, teamID)
+	if err != nil {
+		fmt.Printf("Erro ao projetar elenco: %v\n", err)
+		return projection, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var p projectedPlayer
+		var playerID int
+		var name string
+		var classYear models.ClassYear
+		var redshirtYear *int
+		var declared, inPortal bool
+		err := rows.Scan(&playerID, &name, &p.position, &classYear, &redshirtYear, &p.overall, &p.walkOn, &declared, &inPortal)
+		if err != nil {
+			return projection, err
+		}
+		p.classYear, err = ParseClassYear(string(classYear))
+		if err != nil {
+			projection.invalid = append(projection.invalid, InvalidClassYear{PlayerID: playerID, Name: name, ClassYear: string(classYear)})
+			continue
+		}
+
+		var exhausted bool
+		p.newClass, exhausted = nextClassYear(p.classYear, redshirtYear != nil && *redshirtYear == year)
+		switch {
+		case declared:
+			p.outcome = projectionDeclared
+		case exhausted:
+			p.outcome = projectionGraduating
+		case inPortal:
+			p.outcome = projectionInPortal
+		default:
+			p.outcome = projectionStays
+		}
+		projection.players = append(projection.players, p)
+	}
+
+	recruitRows, err := database.DB.Query(`
+        SELECT position, class FROM recruits r
+        WHERE r.team_id = ? AND r.recruitment_year = ?
+          AND NOT EXISTS (SELECT 1 FROM players p WHERE p.recruit_id = r.recruit_id)
+    `, teamID, year)
+	if err != nil {
+		fmt.Printf("Erro ao buscar recrutas assinados: %v\n", err)
+		return projection, err
+	}
+	defer recruitRows.Close()
+	for recruitRows.Next() {
+		var r projectedPlayer
+		var class string
+		if err := recruitRows.Scan(&r.position, &class); err != nil {
+			return projection, err
+		}
+		// Como na promoção, recrutas sem ano legível chegam como FR
+		classYear, err := ParseClassYear(class)
+		if err != nil {
+			classYear = models.ClassFreshman
+		}
+		r.classYear, r.newClass, r.outcome = classYear, classYear, projectionStays
+		projection.recruits = append(projection.recruits, r)
+	}
+	return projection, nil
+}
+
+// GetRosterCompliance lista as violações atuais do elenco e as previstas após a virada da
+// temporada, com o elenco projetado por projectRollover
+func GetRosterCompliance(teamID int, year int) (RosterCompliance, error) {
+	compliance := RosterCompliance{TeamID: teamID, Year: year}
+
+	rules, err := GetRosterRules(teamID)
+	if err != nil {
+		return compliance, err
+	}
+	compliance.Rules = rules
+
+	compliance.Current, err = countRoster(database.DB, teamID)
+	if err != nil {
+		return compliance, err
+	}
+	compliance.Violations = checkRosterRules(rules, compliance.Current)
+
+	projection, err := projectRollover(teamID, year)
+	if err != nil {
+		return compliance, err
+	}
+	compliance.InvalidClassYears = projection.invalid
+
+	projected := newRosterCounts()
+	for _, p := range projection.players {
+		if p.outcome == projectionStays {
+			projected.add(p.position, p.newClass, p.walkOn, 1)
+		}
+	}
+	for _, r := range projection.recruits {
+		projected.add(r.position, r.newClass, false, 1)
+	}
+
+	compliance.Projected = projected
+	compliance.AfterRollover = checkRosterRules(rules, projected)
+	return compliance, nil
+}
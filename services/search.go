@@ -0,0 +1,321 @@
+package services
+
+import (
+	"dynastyTracker/database"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Tipos de resultado da busca
+const (
+	SearchPlayer   = "player"
+	SearchRecruit  = "recruit"
+	SearchRecord   = "record"
+	SearchTeam     = "team"
+	SearchOpponent = "opponent"
+)
+
+type SearchHit struct {
+	Type   string  `json:"type"`
+	ID     int     `json:"id,omitempty"` // Adversários não têm ID; o nome identifica o resultado
+	Name   string  `json:"name"`
+	Detail string  `json:"detail"`
+	Score  float64 `json:"score"`
+}
+
+type searchCandidate struct {
+	hit   SearchHit
+	texts []string // Nomes pelos quais o resultado pode ser encontrado (ex: escola, mascote, sigla)
+}
+
+var accentReplacer = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a", "å", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n", "ý", "y", "ÿ", "y",
+)
+
+// Apelidos comuns: todos os nomes de um grupo são tratados como equivalentes
+var nicknameGroups = [][]string{
+	{"john", "jon", "johnny", "jonathan", "jonny"},
+	{"michael", "mike", "mikey"},
+	{"william", "will", "bill", "billy", "willie", "liam"},
+	{"robert", "rob", "bob", "bobby", "robbie"},
+	{"james", "jim", "jimmy", "jamie"},
+	{"thomas", "tom", "tommy"},
+	{"christopher", "chris"},
+	{"matthew", "matt"},
+	{"nicholas", "nick", "nico"},
+	{"daniel", "dan", "danny"},
+	{"joseph", "joe", "joey"},
+	{"anthony", "tony"},
+	{"alexander", "alex"},
+	{"benjamin", "ben", "benny"},
+	{"samuel", "sam", "sammy"},
+	{"david", "dave"},
+	{"steven", "stephen", "steve"},
+	{"joshua", "josh"},
+	{"zachary", "zach", "zack", "zac"},
+	{"jacob", "jake"},
+	{"andrew", "drew", "andy"},
+	{"edward", "ed", "eddie"},
+	{"gregory", "greg"},
+	{"patrick", "pat"},
+	{"timothy", "tim", "timmy"},
+	{"kenneth", "ken", "kenny"},
+	{"richard", "rich", "rick", "ricky", "dick"},
+	{"charles", "charlie", "chuck"},
+	{"donald", "don", "donnie"},
+	{"jeffrey", "jeff"},
+	{"cameron", "cam"},
+	{"dominic", "dom"},
+	{"deshawn", "deshaun"},
+	{"jamarcus", "marcus"},
+}
+
+var nicknames = buildNicknameIndex()
+
+func buildNicknameIndex() map[string]string {
+	index := make(map[string]string)
+	for _, group := range nicknameGroups {
+		for _, name := range group {
+			index[name] = group[0]
+		}
+	}
+	return index
+}
+
+// normalizeSearchText deixa o texto em minúsculas, sem acentos e sem pontuação
+func normalizeSearchText(text string) []string {
+	text = accentReplacer.Replace(strings.ToLower(text))
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+}
+
+// editDistance calcula a distância de Levenshtein entre duas palavras
+func editDistance(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// tokenScore avalia uma palavra da busca contra uma palavra do resultado
+func tokenScore(query string, candidate string) float64 {
+	switch {
+	case query == candidate:
+		return 1.0
+	case nicknames[query] != "" && nicknames[query] == nicknames[candidate]:
+		return 0.9
+	case len(query) >= 2 && strings.HasPrefix(candidate, query):
+		return 0.8
+	}
+
+	// Tolerância a erros de digitação cresce com o tamanho da palavra
+	allowed := 0
+	if len(query) >= 3 {
+		allowed = 1
+	}
+	if len(query) >= 6 {
+		allowed = 2
+	}
+	if allowed > 0 {
+		if distance := editDistance(query, candidate); distance <= allowed {
+			return 0.75 - 0.15*float64(distance-1)
+		}
+	}
+	return 0
+}
+
+// matchScore exige que todas as palavras da busca encontrem uma palavra no texto e devolve a média
+func matchScore(query []string, text string) float64 {
+	tokens := normalizeSearchText(text)
+	if len(tokens) == 0 {
+		return 0
+	}
+
+	total := 0.0
+	for _, q := range query {
+		best := 0.0
+		for _, token := range tokens {
+			if score := tokenScore(q, token); score > best {
+				best = score
+			}
+		}
+		if best == 0 {
+			return 0
+		}
+		total += best
+	}
+
+	score := total / float64(len(query))
+	if strings.Join(tokens, " ") == strings.Join(query, " ") {
+		score += 0.1 // Nome completo idêntico fica acima de correspondências parciais
+	}
+	return score
+}
+
+func loadSearchCandidates(types map[string]bool) ([]searchCandidate, error) {
+	var candidates []searchCandidate
+
+	load := func(kind string, query string, build func(scan func(dest ...interface{}) error) (searchCandidate, error)) error {
+		if len(types) > 0 && !types[kind] {
+			return nil
+		}
+		rows, err := database.DB.Query(query)
+		if err != nil {
+			fmt.Printf("Erro ao carregar dados para busca: %v\n", err)
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			candidate, err := build(rows.Scan)
+			if err != nil {
+				return err
+			}
+			candidate.hit.Type = kind
+			candidates = append(candidates, candidate)
+		}
+		return rows.Err()
+	}
+
+	err := load(SearchPlayer, "SELECT player_id, name, position, class_year, active FROM players",
+		func(scan func(dest ...interface{}) error) (searchCandidate, error) {
+			var c searchCandidate
+			var position, classYear string
+			var active bool
+			err := scan(&c.hit.ID, &c.hit.Name, &position, &classYear, &active)
+			c.hit.Detail = fmt.Sprintf("%s %s", position, classYear)
+			if !active {
+				c.hit.Detail += " (fora do elenco)"
+			}
+			c.texts = []string{c.hit.Name}
+			return c, err
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	err = load(SearchRecruit, "SELECT recruit_id, player_name, position, stars, recruitment_year, COALESCE(hometown, ''), COALESCE(home_state, '') FROM recruits",
+		func(scan func(dest ...interface{}) error) (searchCandidate, error) {
+			var c searchCandidate
+			var position, hometown, homeState string
+			var stars, year int
+			err := scan(&c.hit.ID, &c.hit.Name, &position, &stars, &year, &hometown, &homeState)
+			c.hit.Detail = fmt.Sprintf("%s %d estrelas, classe de %d - %s, %s", position, stars, year, hometown, homeState)
+			c.texts = []string{c.hit.Name}
+			return c, err
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	err = load(SearchRecord, "SELECT record_id, player_name, school, year_start, year_end FROM historicalrecords",
+		func(scan func(dest ...interface{}) error) (searchCandidate, error) {
+			var c searchCandidate
+			var school string
+			var yearStart, yearEnd int
+			err := scan(&c.hit.ID, &c.hit.Name, &school, &yearStart, &yearEnd)
+			c.hit.Detail = fmt.Sprintf("%s %d-%d", school, yearStart, yearEnd)
+			c.texts = []string{c.hit.Name}
+			return c, err
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	err = load(SearchTeam, "SELECT team_id, school, COALESCE(mascot, ''), COALESCE(abbreviation, ''), COALESCE(alt_name1, '') FROM teams",
+		func(scan func(dest ...interface{}) error) (searchCandidate, error) {
+			var c searchCandidate
+			var mascot, abbreviation, altName string
+			err := scan(&c.hit.ID, &c.hit.Name, &mascot, &abbreviation, &altName)
+			c.hit.Detail = strings.TrimSpace(mascot + " " + abbreviation)
+			c.texts = []string{c.hit.Name, c.hit.Name + " " + mascot, abbreviation, altName}
+			return c, err
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	err = load(SearchOpponent, "SELECT opponent, COUNT(*), MIN(year), MAX(year) FROM schedule GROUP BY opponent",
+		func(scan func(dest ...interface{}) error) (searchCandidate, error) {
+			var c searchCandidate
+			var games, firstYear, lastYear int
+			err := scan(&c.hit.Name, &games, &firstYear, &lastYear)
+			c.hit.Detail = fmt.Sprintf("%d jogos (%d-%d)", games, firstYear, lastYear)
+			c.texts = []string{c.hit.Name}
+			return c, err
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return candidates, nil
+}
+
+// Search procura jogadores, recrutas, recordes, times e adversários com tolerância a erros de
+// digitação, acentos, apelidos e prefixos. types limita os tipos de resultado (vazio = todos)
+func Search(query string, types []string, limit int) ([]SearchHit, error) {
+	terms := normalizeSearchText(query)
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("informe o texto da busca")
+	}
+	if limit <= 0 {
+		limit = 25
+	}
+
+	typeFilter := make(map[string]bool)
+	for _, kind := range types {
+		if kind != "" {
+			typeFilter[kind] = true
+		}
+	}
+
+	candidates, err := loadSearchCandidates(typeFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	var hits []SearchHit
+	for _, candidate := range candidates {
+		best := 0.0
+		for _, text := range candidate.texts {
+			if score := matchScore(terms, text); score > best {
+				best = score
+			}
+		}
+		if best > 0 {
+			hit := candidate.hit
+			hit.Score = best
+			hits = append(hits, hit)
+		}
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Name < hits[j].Name
+	})
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits, nil
+}
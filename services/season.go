@@ -0,0 +1,211 @@
+package services
+
+import (
+	"database/sql"
+	"dynastyTracker/database"
+	"dynastyTracker/models"
+	"errors"
+	"fmt"
+)
+
+// ErrSeasonAlreadyRolledOver impede repetir a virada de uma temporada, o que avançaria o ano
+// acadêmico dos jogadores duas vezes
+var ErrSeasonAlreadyRolledOver = errors.New("a virada desta temporada já foi feita")
+
+type ClassAdvancement struct {
+	PlayerID      int              `json:"player_id"`
+	Name          string           `json:"name"`
+	PreviousClass models.ClassYear `json:"previous_class"`
+	NewClass      models.ClassYear `json:"new_class"`
+	GamesPlayed   int              `json:"games_played"`
+	Redshirted    bool             `json:"redshirted"` // Redshirt usado na temporada encerrada
+}
+
+type SeasonRolloverReport struct {
+	Year             int                `json:"year"`
+	Advanced         []ClassAdvancement `json:"advanced"`
+	Exhausted        []ClassAdvancement `json:"exhausted"`          // Elegibilidade esgotada: saem do elenco como graduados
+	RedshirtsRevoked []ClassAdvancement `json:"redshirts_revoked"`  // Redshirts perdidos por passar de quatro jogos
+	DeclaredForDraft []ClassAdvancement `json:"declared_for_draft"` // Removidos do elenco ativo
+	Promotion        PromotionReport    `json:"promotion"`          // Recrutas promovidos, vinculados e deixados de fora
+
+	InvalidClassYears []InvalidClassYear `json:"invalid_class_years"` // Não avançaram; corrija o ano acadêmico e ajuste à mão
+}
+
+// RolloverSeason encerra a temporada: remove do elenco ativo quem se declarou para o
+// draft, avança o ano acadêmico dos jogadores, gradua quem esgotou a elegibilidade e
+// promove os recrutas da classe assinada, tudo em uma transação. As saídas ficam registradas
+// em departures. Jogadores com ano acadêmico ilegível são pulados e listados no relatório.
+// Cada temporada só pode virar uma vez
+func RolloverSeason(year int) (SeasonRolloverReport, error) {
+	report := SeasonRolloverReport{Year: year, InvalidClassYears: []InvalidClassYear{}}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return report, err
+	}
+	defer tx.Rollback()
+
+	// A virada fica registrada na mesma transação; a chave única em season_rollovers.year
+	// barra também duas viradas simultâneas
+	_, err = tx.Exec("INSERT INTO season_rollovers (year) VALUES (?)", year)
+	if database.IsDuplicateKey(err) {
+		return report, ErrSeasonAlreadyRolledOver
+	}
+	if err != nil {
+		fmt.Printf("Erro ao registrar a virada de temporada: %v\n", err)
+		return report, err
+	}
+
+	// Guardar o overall de fim de temporada no histórico de ratings
+	_, err = tx.Exec(`
+        INSERT INTO player_ratings (player_id, year, overall)
+        SELECT player_id, ?, overall FROM players WHERE active = 1 AND eligibility_exhausted = 0
+        ON DUPLICATE KEY UPDATE overall = VALUES(overall)
+    `, year)
+	if err != nil {
+		fmt.Printf("Erro ao registrar ratings da temporada: %v\n", err)
+		return report, err
+	}
+
+	report.DeclaredForDraft, err = removeDraftDeclarations(tx, year)
+	if err != nil {
+		return report, err
+	}
+
+	rows, err := tx.Query(`
+        SELECT p.player_id, p.name, p.class_year, p.redshirt_year, COALESCE(gp.games, 0) AS games_played
+        FROM players p
+        LEFT JOIN (
+            SELECT g.player_id, COUNT(DISTINCT g.schedule_id) AS games
+            FROM playergamestats g
+            JOIN schedule s ON g.schedule_id = s.id
+            WHERE s.year = ?
+            GROUP BY g.player_id
+        ) gp ON gp.player_id = p.player_id
+        WHERE p.active = 1 AND p.eligibility_exhausted = 0
+    `, year)
+	if err != nil {
+		fmt.Printf("Erro ao buscar jogadores para a virada de temporada: %v\n", err)
+		return report, err
+	}
+
+	var advancements []ClassAdvancement
+	for rows.Next() {
+		var item ClassAdvancement
+		var redshirtYear sql.NullInt64
+		if err := rows.Scan(&item.PlayerID, &item.Name, &item.PreviousClass, &redshirtYear, &item.GamesPlayed); err != nil {
+			rows.Close()
+			fmt.Printf("Erro ao escanear jogador: %v\n", err)
+			return report, err
+		}
+		item.Redshirted = redshirtYear.Valid && int(redshirtYear.Int64) == year
+		advancements = append(advancements, item)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return report, err
+	}
+
+	for _, item := range advancements {
+		classYear, err := ParseClassYear(string(item.PreviousClass))
+		if err != nil {
+			fmt.Printf("Ano acadêmico inválido para o jogador %d: %v\n", item.PlayerID, err)
+			report.InvalidClassYears = append(report.InvalidClassYears,
+				InvalidClassYear{PlayerID: item.PlayerID, Name: item.Name, ClassYear: string(item.PreviousClass)})
+			continue
+		}
+
+		if item.Redshirted && item.GamesPlayed > maxRedshirtGames {
+			// Passou do limite de jogos: perde o redshirt e avança normalmente
+			item.Redshirted = false
+			_, err = tx.Exec("UPDATE players SET redshirted = 0, redshirt_year = NULL WHERE player_id = ?", item.PlayerID)
+			if err != nil {
+				return report, err
+			}
+			report.RedshirtsRevoked = append(report.RedshirtsRevoked, item)
+		}
+
+		newClass, exhausted := nextClassYear(classYear, item.Redshirted)
+		item.NewClass = newClass
+		_, err = tx.Exec("UPDATE players SET class_year = ?, eligibility_exhausted = ? WHERE player_id = ?",
+			newClass, exhausted, item.PlayerID)
+		if err != nil {
+			fmt.Printf("Erro ao avançar ano acadêmico: %v\n", err)
+			return report, err
+		}
+
+		if exhausted {
+			err = recordDeparture(tx, models.Departure{PlayerID: item.PlayerID, Year: year, Reason: DepartureGraduated})
+			if err != nil {
+				return report, err
+			}
+			report.Exhausted = append(report.Exhausted, item)
+		} else {
+			report.Advanced = append(report.Advanced, item)
+		}
+	}
+
+	// Quem continua no elenco mantém o número na próxima temporada
+	_, err = tx.Exec(`
+        INSERT IGNORE INTO player_jerseys (player_id, year, team_id, number)
+        SELECT j.player_id, ?, p.team_id, j.number
+        FROM player_jerseys j
+        JOIN players p ON p.player_id = j.player_id
+        WHERE j.year = ? AND p.active = 1 AND p.eligibility_exhausted = 0
+    `, year+1, year)
+	if err != nil {
+		fmt.Printf("Erro ao manter números para a próxima temporada: %v\n", err)
+		return report, err
+	}
+
+	// A classe assinada durante a temporada entra no elenco da próxima
+	promotion, promotedTeams, err := promoteRecruits(tx, year+1)
+	report.Promotion = promotion
+	if err != nil {
+		return report, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return report, err
+	}
+	// A promoção já foi gravada; sem sugestões, os números são escolhidos à mão
+	if err := addJerseySuggestions(&report.Promotion, promotedTeams); err != nil {
+		fmt.Printf("Erro ao sugerir números: %v\n", err)
+	}
+	return report, nil
+}
+
+// removeDraftDeclarations registra a saída dos jogadores declarados para o draft da temporada
+func removeDraftDeclarations(tx *sql.Tx, year int) ([]ClassAdvancement, error) {
+	rows, err := tx.Query(`
+        SELECT p.player_id, p.name, p.class_year
+        FROM players p
+        JOIN draft_declarations d ON d.player_id = p.player_id
+        WHERE d.year = ? AND p.active = 1
+    `, year)
+	if err != nil {
+		fmt.Printf("Erro ao buscar declarados para o draft: %v\n", err)
+		return nil, err
+	}
+
+	var declared []ClassAdvancement
+	for rows.Next() {
+		var item ClassAdvancement
+		if err := rows.Scan(&item.PlayerID, &item.Name, &item.PreviousClass); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		item.NewClass = item.PreviousClass
+		declared = append(declared, item)
+	}
+	rows.Close()
+
+	for _, item := range declared {
+		err = recordDeparture(tx, models.Departure{PlayerID: item.PlayerID, Year: year, Reason: DepartureDeclared, Destination: "NFL Draft"})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return declared, nil
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"dynastyTracker/database"
 	"dynastyTracker/models"
+	"errors"
 	"fmt"
 )
 
@@ -58,3 +59,58 @@ func AssignTeamToCoach(assignment models.TeamAssignment) error {
 	}
 	return nil
 }
+
+// ErrInvalidTeamConference indica dados de conferência incompletos ou inválidos
+var ErrInvalidTeamConference = errors.New("conferência inválida")
+
+// SetTeamConference define a conferência do time a partir da temporada informada
+func SetTeamConference(membership models.TeamConference) error {
+	if membership.Conference == "" {
+		return fmt.Errorf("%w: informe a conferência", ErrInvalidTeamConference)
+	}
+	if membership.Year <= 0 {
+		return fmt.Errorf("%w: ano inválido: %d", ErrInvalidTeamConference, membership.Year)
+	}
+	var teamID int
+	err := database.DB.QueryRow("SELECT team_id FROM teams WHERE team_id = ?", membership.TeamID).Scan(&teamID)
+	if err != nil {
+		return err
+	}
+
+	_, err = database.DB.Exec(`
+        INSERT INTO team_conferences (team_id, year, conference) VALUES (?, ?, ?)
+        ON DUPLICATE KEY UPDATE conference = VALUES(conference)
+    `, membership.TeamID, membership.Year, membership.Conference)
+	if err != nil {
+		return fmt.Errorf("erro ao definir conferência: %v", err)
+	}
+	return nil
+}
+
+// GetTeamConferences devolve a conferência de cada time na temporada: vale o registro mais
+// recente até o ano informado
+func GetTeamConferences(year int) (map[int]string, error) {
+	rows, err := database.DB.Query(`
+        SELECT c.team_id, c.conference
+        FROM team_conferences c
+        WHERE c.year = (SELECT MAX(c2.year) FROM team_conferences c2 WHERE c2.team_id = c.team_id AND c2.year <= ?)
+    `, year)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar conferências: %v", err)
+	}
+	defer rows.Close()
+
+	conferences := make(map[int]string)
+	for rows.Next() {
+		var teamID int
+		var conference string
+		if err := rows.Scan(&teamID, &conference); err != nil {
+			return nil, fmt.Errorf("erro ao ler conferência: %v", err)
+		}
+		conferences[teamID] = conference
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao ler conferências: %v", err)
+	}
+	return conferences, nil
+}
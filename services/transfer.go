@@ -0,0 +1,484 @@
+package services
+
+import (
+	"database/sql"
+	"dynastyTracker/database"
+	"dynastyTracker/models"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var (
+	ErrTransferNotFound = errors.New("transferência não encontrada")
+	ErrTransferClosed   = errors.New("transferência não está aberta no portal")
+	ErrInvalidTransfer  = errors.New("transferência inválida")
+	ErrAlreadyInPortal  = errors.New("o jogador já está no portal")
+)
+
+// Direção e situação das movimentações do portal
+const (
+	TransferIncoming = "incoming"
+	TransferOutgoing = "outgoing"
+
+	PortalInPortal  = "in_portal"
+	PortalCommitted = "committed"
+	PortalWithdrawn = "withdrawn"
+)
+
+// Fonte de recrutamento dos recrutas criados pelo portal
+const recruitmentSourcePortal = "Transfer Portal"
+
+const transferColumns = `t.transfer_id, t.direction, t.team_id, t.year, t.player_name, t.position, t.overall, t.class_year,
+        t.eligibility_remaining, COALESCE(t.origin_school, ''), COALESCE(t.destination, ''), t.entry_week, t.commit_week,
+        t.status, t.player_id, t.recruit_id, t.person_id, COALESCE(t.notes, '')`
+
+func scanTransfer(row rowScanner) (models.PortalTransfer, error) {
+	var t models.PortalTransfer
+	err := row.Scan(&t.TransferID, &t.Direction, &t.TeamID, &t.Year, &t.PlayerName, &t.Position, &t.Overall, &t.ClassYear,
+		&t.EligibilityRemaining, &t.OriginSchool, &t.Destination, &t.EntryWeek, &t.CommitWeek,
+		&t.Status, &t.PlayerID, &t.RecruitID, &t.PersonID, &t.Notes)
+	return t, err
+}
+
+func insertTransfer(exec sqlExecer, t models.PortalTransfer) (int, error) {
+	result, err := exec.Exec(`
+        INSERT INTO portal_transfers (direction, team_id, year, player_name, position, overall, class_year, eligibility_remaining,
+            origin_school, destination, entry_week, commit_week, status, player_id, recruit_id, person_id, notes)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), ?, ?, ?, ?, ?, ?, NULLIF(?, ''))
+    `, t.Direction, t.TeamID, t.Year, t.PlayerName, t.Position, t.Overall, t.ClassYear, t.EligibilityRemaining,
+		t.OriginSchool, t.Destination, t.EntryWeek, t.CommitWeek, t.Status, t.PlayerID, t.RecruitID, t.PersonID, t.Notes)
+	if err != nil {
+		fmt.Printf("Erro ao registrar transferência: %v\n", err)
+		return 0, err
+	}
+	id, err := result.LastInsertId()
+	return int(id), err
+}
+
+// AddIncomingTransfer registra um jogador de outra escola no portal que é alvo do time. As
+// estatísticas da escola anterior podem vir junto (PriorStats)
+func AddIncomingTransfer(transfer models.PortalTransfer) (int, error) {
+	if transfer.OriginSchool == "" {
+		return 0, fmt.Errorf("%w: informe a escola de origem", ErrInvalidTransfer)
+	}
+	classYear, err := ParseClassYear(string(transfer.ClassYear))
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidTransfer, err)
+	}
+	if transfer.EligibilityRemaining < 1 {
+		return 0, fmt.Errorf("%w: sem elegibilidade restante", ErrInvalidTransfer)
+	}
+	transfer.ClassYear, transfer.Direction = classYear, TransferIncoming
+	transfer.Status, transfer.CommitWeek, transfer.PlayerID, transfer.RecruitID = PortalInPortal, nil, nil, nil
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	personID, err := resolvePerson(tx, transfer.PersonID, transfer.PlayerName)
+	if err != nil {
+		return 0, err
+	}
+	transfer.PersonID = &personID
+
+	id, err := insertTransfer(tx, transfer)
+	if err != nil {
+		return 0, err
+	}
+	if err := saveTransferPriorStats(tx, id, transfer.PriorStats); err != nil {
+		return 0, err
+	}
+	return id, tx.Commit()
+}
+
+// AddOutgoingTransfer registra a entrada de um jogador do elenco no portal. Ele continua no elenco
+// até se comprometer com outra escola (CommitTransfer) ou desistir (WithdrawTransfer)
+func AddOutgoingTransfer(playerID int, year int, entryWeek *int, notes string) (int, error) {
+	player, err := GetPlayer(playerID)
+	if err != nil {
+		return 0, err
+	}
+	if !player.Active {
+		return 0, fmt.Errorf("%w: o jogador %d já não faz parte do elenco", ErrInvalidTransfer, playerID)
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Travar o jogador serializa entradas simultâneas no portal; a segunda já vê a primeira
+	var locked int
+	if err := tx.QueryRow("SELECT player_id FROM players WHERE player_id = ? FOR UPDATE", playerID).Scan(&locked); err != nil {
+		return 0, err
+	}
+	var open int
+	err = tx.QueryRow("SELECT COUNT(*) FROM portal_transfers WHERE player_id = ? AND direction = ? AND status = ?",
+		playerID, TransferOutgoing, PortalInPortal).Scan(&open)
+	if err != nil {
+		return 0, err
+	}
+	if open > 0 {
+		return 0, ErrAlreadyInPortal
+	}
+
+	id, err := insertTransfer(tx, models.PortalTransfer{
+		Direction:            TransferOutgoing,
+		TeamID:               player.TeamID,
+		Year:                 year,
+		PlayerName:           player.Name,
+		Position:             player.Position,
+		Overall:              player.Overall,
+		ClassYear:            player.ClassYear,
+		EligibilityRemaining: remainingEligibility(player.ClassYear),
+		EntryWeek:            entryWeek,
+		Status:               PortalInPortal,
+		PlayerID:             &playerID,
+		PersonID:             player.PersonID,
+		Notes:                notes,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, tx.Commit()
+}
+
+// GetTransfer obtém uma movimentação do portal com as estatísticas anteriores
+func GetTransfer(id int) (models.PortalTransfer, error) {
+	transfer, err := scanTransfer(database.DB.QueryRow("SELECT "+transferColumns+" FROM portal_transfers t WHERE t.transfer_id = ?", id))
+	if err != nil {
+		return transfer, err
+	}
+	transfer.PriorStats, err = getTransferPriorStats(id)
+	return transfer, err
+}
+
+type TransferPage struct {
+	Transfers []models.PortalTransfer `json:"transfers"`
+	PageInfo
+}
+
+// Campos aceitos na ordenação da listagem do portal
+var transferSortColumns = map[string]string{
+	"name":       "t.player_name",
+	"position":   "t.position",
+	"overall":    "t.overall",
+	"year":       "t.year",
+	"entry_week": "t.entry_week",
+	"status":     "t.status",
+}
+
+// transferFilter monta o WHERE das listagens do portal por time, temporada, direção e situação
+func transferFilter(teamID int, year int, direction string, status string) (string, []interface{}) {
+	where := " WHERE 1=1"
+	var args []interface{}
+	if teamID > 0 {
+		where += " AND t.team_id = ?"
+		args = append(args, teamID)
+	}
+	if year > 0 {
+		where += " AND t.year = ?"
+		args = append(args, year)
+	}
+	if direction != "" {
+		where += " AND t.direction = ?"
+		args = append(args, direction)
+	}
+	if status != "" {
+		where += " AND t.status = ?"
+		args = append(args, status)
+	}
+	return where, args
+}
+
+func queryTransfers(query string, args []interface{}) ([]models.PortalTransfer, error) {
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		fmt.Printf("Erro ao executar consulta: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	transfers := []models.PortalTransfer{}
+	for rows.Next() {
+		transfer, err := scanTransfer(rows)
+		if err != nil {
+			fmt.Printf("Erro ao escanear resultados: %v\n", err)
+			return nil, err
+		}
+		transfers = append(transfers, transfer)
+	}
+	return transfers, nil
+}
+
+// GetTransfersWithFilters lista as movimentações do portal por time, temporada, direção e
+// situação, paginadas e ordenadas
+func GetTransfersWithFilters(teamID int, year int, direction string, status string, options ListOptions) (TransferPage, error) {
+	options = options.normalize()
+	page := TransferPage{Transfers: []models.PortalTransfer{}}
+
+	where, args := transferFilter(teamID, year, direction, status)
+	suffix, pageArgs, err := options.orderAndLimit(transferSortColumns, "t.year, t.direction, t.overall DESC", "t.transfer_id")
+	if err != nil {
+		return page, err
+	}
+
+	var total int
+	if err := database.DB.QueryRow("SELECT COUNT(*) FROM portal_transfers t"+where, args...).Scan(&total); err != nil {
+		fmt.Printf("Erro ao contar transferências: %v\n", err)
+		return page, err
+	}
+	page.PageInfo = options.pageInfo(total)
+
+	page.Transfers, err = queryTransfers("SELECT "+transferColumns+" FROM portal_transfers t"+where+suffix, append(args, pageArgs...))
+	return page, err
+}
+
+// CommitTransfer conclui a movimentação. Chegadas viram recrutas do portal (promovidos a jogador
+// na virada, respeitando as regras de elenco); saídas registram a saída do jogador com o destino
+func CommitTransfer(id int, commitWeek *int, destination string) error {
+	transfer, err := GetTransfer(id)
+	if err == sql.ErrNoRows {
+		return ErrTransferNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if transfer.Status != PortalInPortal {
+		return fmt.Errorf("%w: já está %s", ErrTransferClosed, transfer.Status)
+	}
+	if transfer.Direction == TransferOutgoing && destination == "" {
+		return fmt.Errorf("%w: saídas precisam da escola de destino", ErrInvalidTransfer)
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// A troca de status só vale para quem ainda está no portal, o que impede concluir duas vezes
+	commit := func(destination string, recruitID *int) error {
+		result, err := tx.Exec(`
+            UPDATE portal_transfers SET status = ?, commit_week = ?, destination = NULLIF(?, ''), recruit_id = COALESCE(?, recruit_id)
+            WHERE transfer_id = ? AND status = ?
+        `, PortalCommitted, commitWeek, destination, recruitID, id, PortalInPortal)
+		if err != nil {
+			return err
+		}
+		if affected, _ := result.RowsAffected(); affected == 0 {
+			return ErrTransferClosed
+		}
+		return nil
+	}
+
+	if transfer.Direction == TransferIncoming {
+		// A elegibilidade informada no portal acompanha o recruta; as estatísticas anteriores
+		// ficam ligadas a ele por portal_transfers.recruit_id
+		eligibility := transfer.EligibilityRemaining
+		recruitID, err := addRecruit(tx, models.Recruit{
+			PlayerName:        transfer.PlayerName,
+			Class:             string(transfer.ClassYear),
+			Position:          transfer.Position,
+			Overall:           transfer.Overall,
+			RecruitmentSource: recruitmentSourcePortal,
+			RecruitmentYear:   transfer.Year,
+			TeamID:            transfer.TeamID,
+			PersonID:          transfer.PersonID,
+
+			EligibilityRemaining: &eligibility,
+		})
+		if err != nil {
+			return err
+		}
+		if err := commit("", &recruitID); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+
+	err = recordDeparture(tx, models.Departure{PlayerID: intValue(transfer.PlayerID), Year: transfer.Year,
+		Reason: DepartureTransferred, Destination: destination, Notes: transfer.Notes})
+	if err != nil {
+		return err
+	}
+	if err := commit(destination, nil); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// WithdrawTransfer cancela uma movimentação ainda aberta: o alvo foi para outra escola ou o
+// jogador do elenco desistiu do portal
+func WithdrawTransfer(id int) error {
+	result, err := database.DB.Exec("UPDATE portal_transfers SET status = ? WHERE transfer_id = ? AND status = ?",
+		PortalWithdrawn, id, PortalInPortal)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		var exists int
+		err := database.DB.QueryRow("SELECT COUNT(*) FROM portal_transfers WHERE transfer_id = ?", id).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if exists == 0 {
+			return ErrTransferNotFound
+		}
+		return ErrTransferClosed
+	}
+	return nil
+}
+
+// ImportTransferPriorStats substitui as estatísticas da escola anterior
+func ImportTransferPriorStats(id int, stats []models.TransferSeasonStats) error {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var direction string
+	err = tx.QueryRow("SELECT direction FROM portal_transfers WHERE transfer_id = ?", id).Scan(&direction)
+	if err == sql.ErrNoRows {
+		return ErrTransferNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if direction != TransferIncoming {
+		return fmt.Errorf("%w: estatísticas anteriores só se aplicam a chegadas", ErrInvalidTransfer)
+	}
+
+	if _, err := tx.Exec("DELETE FROM transfer_prior_stats WHERE transfer_id = ?", id); err != nil {
+		return err
+	}
+	if err := saveTransferPriorStats(tx, id, stats); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func saveTransferPriorStats(exec sqlExecer, transferID int, stats []models.TransferSeasonStats) error {
+	for _, s := range stats {
+		_, err := exec.Exec(`
+            INSERT INTO transfer_prior_stats (transfer_id, year, school, games_played, completions, pass_attempts, passing_yards,
+                passing_tds, interceptions, rush_attempts, rushing_yards, rushing_tds, receptions, receiving_yards, receiving_tds,
+                tackles, sacks, def_interceptions)
+            VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+        `, transferID, s.Year, s.School, s.GamesPlayed, s.Completions, s.PassAttempts, s.PassingYards, s.PassingTDs,
+			s.Interceptions, s.RushAttempts, s.RushingYards, s.RushingTDs, s.Receptions, s.ReceivingYards, s.ReceivingTDs,
+			s.Tackles, s.Sacks, s.DefInts)
+		if err != nil {
+			fmt.Printf("Erro ao gravar estatísticas anteriores: %v\n", err)
+			return err
+		}
+	}
+	return nil
+}
+
+func getTransferPriorStats(transferID int) ([]models.TransferSeasonStats, error) {
+	rows, err := database.DB.Query(`
+        SELECT year, school, games_played, completions, pass_attempts, passing_yards, passing_tds, interceptions,
+            rush_attempts, rushing_yards, rushing_tds, receptions, receiving_yards, receiving_tds, tackles, sacks, def_interceptions
+        FROM transfer_prior_stats WHERE transfer_id = ? ORDER BY year
+    `, transferID)
+	if err != nil {
+		fmt.Printf("Erro ao executar consulta: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []models.TransferSeasonStats
+	for rows.Next() {
+		var s models.TransferSeasonStats
+		err := rows.Scan(&s.Year, &s.School, &s.GamesPlayed, &s.Completions, &s.PassAttempts, &s.PassingYards, &s.PassingTDs,
+			&s.Interceptions, &s.RushAttempts, &s.RushingYards, &s.RushingTDs, &s.Receptions, &s.ReceivingYards, &s.ReceivingTDs,
+			&s.Tackles, &s.Sacks, &s.DefInts)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+type PortalPositionDelta struct {
+	Position     string `json:"position"`
+	Incoming     int    `json:"incoming"`
+	Outgoing     int    `json:"outgoing"`
+	Net          int    `json:"net"`
+	OverallIn    int    `json:"overall_in"`  // Soma do overall de quem chegou
+	OverallOut   int    `json:"overall_out"` // Soma do overall de quem saiu
+	OverallDelta int    `json:"overall_delta"`
+}
+
+type PortalReport struct {
+	TeamID            int                     `json:"team_id"`
+	Year              int                     `json:"year"`
+	Incoming          []models.PortalTransfer `json:"incoming"`
+	Outgoing          []models.PortalTransfer `json:"outgoing"`
+	Net               int                     `json:"net"`
+	AverageOverallIn  float64                 `json:"average_overall_in"`
+	AverageOverallOut float64                 `json:"average_overall_out"`
+	OverallDelta      int                     `json:"overall_delta"`
+	EligibilityIn     int                     `json:"eligibility_in"`  // Temporadas de elegibilidade ganhas
+	EligibilityOut    int                     `json:"eligibility_out"` // Temporadas de elegibilidade perdidas
+	ByPosition        []PortalPositionDelta   `json:"by_position"`
+}
+
+// GetPortalReport compara as chegadas e saídas concluídas pelo portal na temporada, com o saldo
+// por posição e a diferença de overall
+func GetPortalReport(teamID int, year int) (PortalReport, error) {
+	report := PortalReport{TeamID: teamID, Year: year}
+
+	// O relatório considera todas as movimentações concluídas, sem paginação
+	where, args := transferFilter(teamID, year, "", PortalCommitted)
+	transfers, err := queryTransfers("SELECT "+transferColumns+" FROM portal_transfers t"+where+" ORDER BY t.year, t.direction, t.overall DESC", args)
+	if err != nil {
+		return report, err
+	}
+
+	positions := make(map[string]*PortalPositionDelta)
+	var overallIn, overallOut int
+	for _, t := range transfers {
+		delta, ok := positions[t.Position]
+		if !ok {
+			delta = &PortalPositionDelta{Position: t.Position}
+			positions[t.Position] = delta
+		}
+		if t.Direction == TransferIncoming {
+			report.Incoming = append(report.Incoming, t)
+			delta.Incoming++
+			delta.OverallIn += t.Overall
+			overallIn += t.Overall
+			report.EligibilityIn += t.EligibilityRemaining
+		} else {
+			report.Outgoing = append(report.Outgoing, t)
+			delta.Outgoing++
+			delta.OverallOut += t.Overall
+			overallOut += t.Overall
+			report.EligibilityOut += t.EligibilityRemaining
+		}
+	}
+
+	report.Net = len(report.Incoming) - len(report.Outgoing)
+	report.OverallDelta = overallIn - overallOut
+	if len(report.Incoming) > 0 {
+		report.AverageOverallIn = float64(overallIn) / float64(len(report.Incoming))
+	}
+	if len(report.Outgoing) > 0 {
+		report.AverageOverallOut = float64(overallOut) / float64(len(report.Outgoing))
+	}
+	for _, delta := range positions {
+		delta.Net = delta.Incoming - delta.Outgoing
+		delta.OverallDelta = delta.OverallIn - delta.OverallOut
+		report.ByPosition = append(report.ByPosition, *delta)
+	}
+	sort.Slice(report.ByPosition, func(i, j int) bool { return report.ByPosition[i].Position < report.ByPosition[j].Position })
+	return report, nil
+}